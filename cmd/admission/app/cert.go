@@ -0,0 +1,233 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	appConf "volcano.sh/volcano/cmd/admission/app/configure"
+)
+
+// CertWatcher keeps the certificate loaded from the cert and key files, and reloads it
+// when the files are rotated, so the server does not need to be restarted.
+type CertWatcher struct {
+	certFile string
+	keyFile  string
+
+	lock    sync.RWMutex
+	certPEM []byte
+	keyPEM  []byte
+	cert    *tls.Certificate
+}
+
+// NewCertWatcher creates a CertWatcher with the certificate loaded from the files.
+func NewCertWatcher(certFile, keyFile string) (*CertWatcher, error) {
+	watcher := &CertWatcher{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if _, err := watcher.reload(); err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// GetCertificate returns the current certificate, it is used as tls.Config.GetCertificate.
+func (w *CertWatcher) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.cert, nil
+}
+
+// Run checks the files every period and reloads the certificate if they are changed, until stopCh is closed.
+func (w *CertWatcher) Run(period time.Duration, stopCh <-chan struct{}) {
+	wait.Until(func() {
+		reloaded, err := w.reload()
+		if err != nil {
+			glog.Errorf("Failed to reload certificate from %s and %s: %v", w.certFile, w.keyFile, err)
+			return
+		}
+		if reloaded {
+			glog.Infof("Certificate is reloaded from %s and %s", w.certFile, w.keyFile)
+		}
+	}, period, stopCh)
+}
+
+// reload loads the certificate if the files are changed; the current certificate is kept
+// if the new one is invalid, e.g. only one of the files is rotated.
+func (w *CertWatcher) reload() (bool, error) {
+	certPEM, err := ioutil.ReadFile(w.certFile)
+	if err != nil {
+		return false, err
+	}
+	keyPEM, err := ioutil.ReadFile(w.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	w.lock.RLock()
+	unchanged := bytes.Equal(certPEM, w.certPEM) && bytes.Equal(keyPEM, w.keyPEM)
+	w.lock.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.certPEM = certPEM
+	w.keyPEM = keyPEM
+	w.cert = &cert
+	return true, nil
+}
+
+// SecretCertWatcher keeps the certificate stored in the Secret of self-managed certificates. It renews the
+// certificates in the Secret before they expire, and serves the certificates updated by any replica.
+type SecretCertWatcher struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	dnsNames  []string
+	// registerCABundle registers the CA bundle in the webhook configs, it is called before serving
+	// the certificate signed by a new CA.
+	registerCABundle func(caBundle []byte) error
+
+	lock     sync.RWMutex
+	certs    *appConf.Certs
+	cert     *tls.Certificate
+	caBundle []byte
+}
+
+// NewSecretCertWatcher creates a SecretCertWatcher with the certificates ensured in the Secret.
+func NewSecretCertWatcher(client kubernetes.Interface, namespace, name string, dnsNames []string,
+	registerCABundle func(caBundle []byte) error) (*SecretCertWatcher, error) {
+	watcher := &SecretCertWatcher{
+		client:           client,
+		namespace:        namespace,
+		name:             name,
+		dnsNames:         dnsNames,
+		registerCABundle: registerCABundle,
+	}
+	certs, err := appConf.EnsureCertSecret(client.CoreV1(), namespace, name, dnsNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure certificates in Secret <%s/%s>: %v", namespace, name, err)
+	}
+	if _, err := watcher.reload(certs); err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// GetCertificate returns the current certificate, it is used as tls.Config.GetCertificate.
+func (w *SecretCertWatcher) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.cert, nil
+}
+
+// Run watches the Secret to serve the certificates updated by other replicas, and checks every period
+// whether the certificates need to be renewed, until stopCh is closed.
+func (w *SecretCertWatcher) Run(period time.Duration, stopCh <-chan struct{}) {
+	update := func(obj interface{}) {
+		secret, ok := obj.(*v1.Secret)
+		if !ok {
+			return
+		}
+		w.reloadOrLog(appConf.CertsFromSecret(secret))
+	}
+
+	lw := cache.NewListWatchFromClient(w.client.CoreV1().RESTClient(), "secrets", w.namespace,
+		fields.OneTermEqualSelector("metadata.name", w.name))
+	_, controller := cache.NewInformer(lw, &v1.Secret{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc: update,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			update(newObj)
+		},
+	})
+	go controller.Run(stopCh)
+
+	// The Secret is recreated if deleted, and the certificates are regenerated if about to expire.
+	wait.Until(func() {
+		certs, err := appConf.EnsureCertSecret(w.client.CoreV1(), w.namespace, w.name, w.dnsNames)
+		if err != nil {
+			glog.Errorf("Failed to ensure certificates in Secret <%s/%s>: %v", w.namespace, w.name, err)
+			return
+		}
+		w.reloadOrLog(certs)
+	}, period, stopCh)
+}
+
+func (w *SecretCertWatcher) reloadOrLog(certs *appConf.Certs) {
+	reloaded, err := w.reload(certs)
+	if err != nil {
+		glog.Errorf("Failed to reload certificate from Secret <%s/%s>: %v", w.namespace, w.name, err)
+		return
+	}
+	if reloaded {
+		glog.Infof("Certificate is reloaded from Secret <%s/%s>", w.namespace, w.name)
+	}
+}
+
+// reload serves the certificates if they are changed; the CA bundle is registered first if it is
+// changed, and the current certificate is kept if the registration fails, so that it is retried.
+func (w *SecretCertWatcher) reload(certs *appConf.Certs) (bool, error) {
+	caBundle := certs.CABundle()
+	w.lock.RLock()
+	certChanged := w.certs == nil || !bytes.Equal(certs.Cert, w.certs.Cert) || !bytes.Equal(certs.Key, w.certs.Key)
+	caChanged := !bytes.Equal(caBundle, w.caBundle)
+	w.lock.RUnlock()
+	if !certChanged && !caChanged {
+		return false, nil
+	}
+
+	cert, err := certs.TLSCertificate()
+	if err != nil {
+		return false, err
+	}
+	if caChanged {
+		if err := w.registerCABundle(caBundle); err != nil {
+			return false, err
+		}
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.certs = certs
+	w.cert = &cert
+	w.caBundle = caBundle
+	return true, nil
+}
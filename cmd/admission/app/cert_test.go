@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	appConf "volcano.sh/volcano/cmd/admission/app/configure"
+)
+
+func writeCert(t *testing.T, dir, commonName string) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, watcher *CertWatcher) string {
+	cert, err := watcher.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("failed to get certificate: %v", err)
+	}
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return parsed.Subject.CommonName
+}
+
+func TestCertWatcherReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cert-watcher")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := writeCert(t, dir, "first")
+	watcher, err := NewCertWatcher(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("failed to create cert watcher: %v", err)
+	}
+	if name := commonName(t, watcher); name != "first" {
+		t.Errorf("expect certificate of first, but got %s", name)
+	}
+
+	if reloaded, err := watcher.reload(); reloaded || err != nil {
+		t.Errorf("expect no reload of unchanged files, but got %v, %v", reloaded, err)
+	}
+
+	// Only the certificate is rotated, the current certificate is kept.
+	keyPEM, _ := ioutil.ReadFile(keyFile)
+	writeCert(t, dir, "second")
+	secondKeyPEM, _ := ioutil.ReadFile(keyFile)
+	ioutil.WriteFile(keyFile, keyPEM, 0600)
+	if _, err := watcher.reload(); err == nil {
+		t.Errorf("expect error of mismatched certificate and key")
+	}
+	if name := commonName(t, watcher); name != "first" {
+		t.Errorf("expect certificate of first, but got %s", name)
+	}
+
+	ioutil.WriteFile(keyFile, secondKeyPEM, 0600)
+	if reloaded, err := watcher.reload(); !reloaded || err != nil {
+		t.Errorf("expect reload of rotated files, but got %v, %v", reloaded, err)
+	}
+	if name := commonName(t, watcher); name != "second" {
+		t.Errorf("expect certificate of second, but got %s", name)
+	}
+}
+
+func TestSecretCertWatcherReload(t *testing.T) {
+	namespace, name := "volcano-system", "volcano-admission-secret"
+	dnsNames := appConf.ServiceDNSNames("volcano-system", "volcano-admission-service")
+	client := fake.NewSimpleClientset()
+
+	var caBundles [][]byte
+	var registerErr error
+	watcher, err := NewSecretCertWatcher(client, namespace, name, dnsNames, func(caBundle []byte) error {
+		if registerErr != nil {
+			return registerErr
+		}
+		caBundles = append(caBundles, caBundle)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to create secret cert watcher: %v", err)
+	}
+	if len(caBundles) != 1 {
+		t.Fatalf("expect CA bundle registered once, but got %d", len(caBundles))
+	}
+	first, _ := watcher.GetCertificate(nil)
+
+	certs, err := appConf.EnsureCertSecret(client.CoreV1(), namespace, name, dnsNames)
+	if err != nil {
+		t.Fatalf("failed to ensure certificates: %v", err)
+	}
+	if reloaded, err := watcher.reload(certs); reloaded || err != nil {
+		t.Errorf("expect no reload of unchanged certificates, but got %v, %v", reloaded, err)
+	}
+
+	// The CA is rotated in the Secret, e.g. by another replica.
+	secret, err := client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	secret.Data[appConf.CAKeyKey] = nil
+	if _, err := client.CoreV1().Secrets(namespace).Update(secret); err != nil {
+		t.Fatalf("failed to update Secret: %v", err)
+	}
+	rotated, err := appConf.EnsureCertSecret(client.CoreV1(), namespace, name, dnsNames)
+	if err != nil {
+		t.Fatalf("failed to rotate certificates: %v", err)
+	}
+
+	// The new certificate is not served until the CA bundle is registered.
+	registerErr = fmt.Errorf("register failed")
+	if _, err := watcher.reload(rotated); err == nil {
+		t.Errorf("expect error of failed registration")
+	}
+	if cert, _ := watcher.GetCertificate(nil); cert != first {
+		t.Errorf("expect current certificate kept")
+	}
+
+	registerErr = nil
+	if reloaded, err := watcher.reload(rotated); !reloaded || err != nil {
+		t.Errorf("expect reload of rotated certificates, but got %v, %v", reloaded, err)
+	}
+	if cert, _ := watcher.GetCertificate(nil); cert == first {
+		t.Errorf("expect rotated certificate served")
+	}
+	if len(caBundles) != 2 || !bytes.Contains(caBundles[1], rotated.CACert) || !bytes.Contains(caBundles[1], rotated.PreviousCACert) {
+		t.Errorf("expect CA bundle of both the new and previous CA registered")
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configure
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// CACertKey is the key of the CA certificate in the certificate Secret.
+	CACertKey = "ca.crt"
+	// CAKeyKey is the key of the CA private key in the certificate Secret.
+	CAKeyKey = "ca.key"
+	// PreviousCACertKey is the key of the CA certificate replaced by the current one in the certificate Secret.
+	PreviousCACertKey = "ca-previous.crt"
+
+	caCommonName = "volcano-admission-ca"
+	// certRenewBefore is how long before expiration the certificates are regenerated.
+	certRenewBefore = 30 * 24 * time.Hour
+)
+
+// Certs are the self-signed CA and the serving certificate signed by it, all PEM encoded.
+type Certs struct {
+	CACert []byte
+	CAKey  []byte
+	Cert   []byte
+	Key    []byte
+	// PreviousCACert is the CA replaced by CACert, it is trusted until it expires, so the serving
+	// certificates signed by it are still accepted while the replicas are switching to the new ones.
+	PreviousCACert []byte
+}
+
+// CABundle returns the CA certificates to register in the webhook configs, including the previous CA.
+func (c *Certs) CABundle() []byte {
+	if len(c.PreviousCACert) == 0 {
+		return c.CACert
+	}
+	bundle := make([]byte, 0, len(c.CACert)+len(c.PreviousCACert))
+	bundle = append(bundle, c.CACert...)
+	return append(bundle, c.PreviousCACert...)
+}
+
+// TLSCertificate returns the serving certificate for the tls config.
+func (c *Certs) TLSCertificate() (tls.Certificate, error) {
+	return tls.X509KeyPair(c.Cert, c.Key)
+}
+
+// ServiceDNSNames returns the DNS names of a service, which are used as the alternative names
+// of the serving certificate.
+func ServiceDNSNames(namespace, name string) []string {
+	return []string{
+		name,
+		fmt.Sprintf("%s.%s", name, namespace),
+		fmt.Sprintf("%s.%s.svc", name, namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", name, namespace),
+	}
+}
+
+// EnsureCertSecret returns the certificates stored in the Secret. The certificates are generated and
+// stored if the Secret does not exist, or regenerated if they are invalid for the DNS names or about
+// to expire. All the replicas of admission server share the same certificates: the replica losing the
+// race of creating or updating the Secret uses the certificates stored by the winner.
+func EnsureCertSecret(client corev1client.SecretsGetter, namespace, name string, dnsNames []string) (*Certs, error) {
+	var certs *Certs
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := client.Secrets(namespace).Get(name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			generated, err := generateCerts(nil, dnsNames, time.Now())
+			if err != nil {
+				return err
+			}
+			secret = &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      name,
+				},
+				Type: v1.SecretTypeOpaque,
+				Data: certsToData(generated),
+			}
+			if _, err = client.Secrets(namespace).Create(secret); err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					// Created by another replica, retry to use the stored certificates.
+					return apierrors.NewConflict(v1.Resource("secrets"), name, err)
+				}
+				return err
+			}
+			glog.Infof("Generated admission certificates in Secret <%s/%s>", namespace, name)
+			certs = generated
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		stored := dataToCerts(secret.Data)
+		invalid := validateCerts(stored, dnsNames, time.Now())
+		if invalid == nil {
+			certs = stored
+			return nil
+		}
+		glog.Infof("Regenerating admission certificates in Secret <%s/%s>: %v", namespace, name, invalid)
+
+		generated, err := generateCerts(stored, dnsNames, time.Now())
+		if err != nil {
+			return err
+		}
+		secret.Data = certsToData(generated)
+		if _, err = client.Secrets(namespace).Update(secret); err != nil {
+			return err
+		}
+		certs = generated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+func certsToData(certs *Certs) map[string][]byte {
+	data := map[string][]byte{
+		CACertKey:           certs.CACert,
+		CAKeyKey:            certs.CAKey,
+		v1.TLSCertKey:       certs.Cert,
+		v1.TLSPrivateKeyKey: certs.Key,
+	}
+	if len(certs.PreviousCACert) != 0 {
+		data[PreviousCACertKey] = certs.PreviousCACert
+	}
+	return data
+}
+
+// CertsFromSecret returns the certificates stored in the Secret.
+func CertsFromSecret(secret *v1.Secret) *Certs {
+	return dataToCerts(secret.Data)
+}
+
+func dataToCerts(data map[string][]byte) *Certs {
+	return &Certs{
+		CACert:         data[CACertKey],
+		CAKey:          data[CAKeyKey],
+		Cert:           data[v1.TLSCertKey],
+		Key:            data[v1.TLSPrivateKeyKey],
+		PreviousCACert: data[PreviousCACertKey],
+	}
+}
+
+// validateCerts checks that the serving certificate is signed by the CA, valid for all the DNS names,
+// and not about to expire at now.
+func validateCerts(certs *Certs, dnsNames []string, now time.Time) error {
+	caCert, _, err := parseCA(certs, now)
+	if err != nil {
+		return err
+	}
+	keyPair, err := certs.TLSCertificate()
+	if err != nil {
+		return fmt.Errorf("invalid serving certificate: %v", err)
+	}
+	servingCert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("invalid serving certificate: %v", err)
+	}
+	if now.Add(certRenewBefore).After(servingCert.NotAfter) {
+		return fmt.Errorf("serving certificate expires at %v", servingCert.NotAfter)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	for _, dnsName := range dnsNames {
+		if _, err := servingCert.Verify(x509.VerifyOptions{
+			DNSName:     dnsName,
+			Roots:       roots,
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}); err != nil {
+			return fmt.Errorf("serving certificate is invalid for %s: %v", dnsName, err)
+		}
+	}
+	return nil
+}
+
+// parseCA returns the CA certificate and key, which are invalid if they are about to expire at now.
+func parseCA(certs *Certs, now time.Time) (*x509.Certificate, crypto.Signer, error) {
+	caCerts, err := cert.ParseCertsPEM(certs.CACert)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CA certificate: %v", err)
+	}
+	key, err := cert.ParsePrivateKeyPEM(certs.CAKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CA key: %v", err)
+	}
+	caKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid CA key: unsupported key type %T", key)
+	}
+	if now.Add(certRenewBefore).After(caCerts[0].NotAfter) {
+		return nil, nil, fmt.Errorf("CA certificate expires at %v", caCerts[0].NotAfter)
+	}
+	return caCerts[0], caKey, nil
+}
+
+// unexpiredCACert returns the PEM encoded CA certificate if it is not expired at now, or nil.
+func unexpiredCACert(caCertPEM []byte, now time.Time) []byte {
+	caCerts, err := cert.ParseCertsPEM(caCertPEM)
+	if err != nil || now.After(caCerts[0].NotAfter) {
+		return nil
+	}
+	return caCertPEM
+}
+
+// generateCerts generates a serving certificate for the DNS names; the CA of current certificates is
+// kept if it is still valid, so that the CA bundle of the webhook configurations remains valid. If the
+// CA is regenerated, the current one is kept as the previous CA until it expires.
+func generateCerts(current *Certs, dnsNames []string, now time.Time) (*Certs, error) {
+	if len(dnsNames) == 0 {
+		return nil, fmt.Errorf("no DNS name specified for the serving certificate")
+	}
+
+	certs := &Certs{}
+	var caCert *x509.Certificate
+	var caKey crypto.Signer
+	if current != nil {
+		var err error
+		if caCert, caKey, err = parseCA(current, now); err == nil {
+			certs.CACert, certs.CAKey = current.CACert, current.CAKey
+			certs.PreviousCACert = unexpiredCACert(current.PreviousCACert, now)
+		} else {
+			certs.PreviousCACert = unexpiredCACert(current.CACert, now)
+		}
+	}
+	if caCert == nil {
+		key, err := cert.NewPrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate CA key: %v", err)
+		}
+		if caCert, err = cert.NewSelfSignedCACert(cert.Config{CommonName: caCommonName}, key); err != nil {
+			return nil, fmt.Errorf("failed to generate CA certificate: %v", err)
+		}
+		caKey = key
+		certs.CACert, certs.CAKey = cert.EncodeCertPEM(caCert), cert.EncodePrivateKeyPEM(key)
+	}
+
+	key, err := cert.NewPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serving key: %v", err)
+	}
+	servingCert, err := cert.NewSignedCert(cert.Config{
+		CommonName: dnsNames[0],
+		AltNames:   cert.AltNames{DNSNames: dnsNames},
+		Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, key, caCert, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serving certificate: %v", err)
+	}
+	certs.Cert, certs.Key = cert.EncodeCertPEM(servingCert), cert.EncodePrivateKeyPEM(key)
+
+	return certs, nil
+}
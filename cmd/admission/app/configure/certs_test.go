@@ -0,0 +1,191 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configure
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsureCertSecret(t *testing.T) {
+	namespace, name := "volcano-system", "volcano-admission-secret"
+	dnsNames := ServiceDNSNames("volcano-system", "volcano-admission-service")
+	client := fake.NewSimpleClientset()
+
+	created, err := EnsureCertSecret(client.CoreV1(), namespace, name, dnsNames)
+	if err != nil {
+		t.Fatalf("failed to create certificates: %v", err)
+	}
+	if err := validateCerts(created, dnsNames, time.Now()); err != nil {
+		t.Errorf("expected valid certificates, got %v", err)
+	}
+	secret, err := client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get Secret: %v", err)
+	}
+	if !bytes.Equal(secret.Data[v1.TLSCertKey], created.Cert) || !bytes.Equal(secret.Data[CACertKey], created.CACert) {
+		t.Errorf("expected certificates stored in Secret")
+	}
+
+	// Another replica reuses the stored certificates.
+	reused, err := EnsureCertSecret(client.CoreV1(), namespace, name, dnsNames)
+	if err != nil {
+		t.Fatalf("failed to reuse certificates: %v", err)
+	}
+	if !bytes.Equal(reused.Cert, created.Cert) || !bytes.Equal(reused.Key, created.Key) {
+		t.Errorf("expected stored serving certificate reused")
+	}
+
+	// The serving certificate is regenerated for another service, signed by the same CA.
+	otherDNSNames := ServiceDNSNames("volcano-system", "other-admission-service")
+	regenerated, err := EnsureCertSecret(client.CoreV1(), namespace, name, otherDNSNames)
+	if err != nil {
+		t.Fatalf("failed to regenerate certificates: %v", err)
+	}
+	if bytes.Equal(regenerated.Cert, created.Cert) {
+		t.Errorf("expected serving certificate regenerated")
+	}
+	if !bytes.Equal(regenerated.CACert, created.CACert) {
+		t.Errorf("expected CA certificate kept")
+	}
+	if err := validateCerts(regenerated, otherDNSNames, time.Now()); err != nil {
+		t.Errorf("expected valid certificates, got %v", err)
+	}
+}
+
+func TestValidateCerts(t *testing.T) {
+	dnsNames := ServiceDNSNames("volcano-system", "volcano-admission-service")
+	certs, err := generateCerts(nil, dnsNames, time.Now())
+	if err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+	other, err := generateCerts(nil, dnsNames, time.Now())
+	if err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+
+	testCases := []struct {
+		Name      string
+		Certs     *Certs
+		DNSNames  []string
+		Now       time.Time
+		ExpectErr bool
+	}{
+		{
+			Name:      "valid",
+			Certs:     certs,
+			DNSNames:  dnsNames,
+			Now:       time.Now(),
+			ExpectErr: false,
+		},
+		{
+			Name:      "mismatched dns names",
+			Certs:     certs,
+			DNSNames:  []string{"volcano-admission-service.default.svc"},
+			Now:       time.Now(),
+			ExpectErr: true,
+		},
+		{
+			Name:      "about to expire",
+			Certs:     certs,
+			DNSNames:  dnsNames,
+			Now:       time.Now().Add(350 * 24 * time.Hour),
+			ExpectErr: true,
+		},
+		{
+			Name: "signed by another CA",
+			Certs: &Certs{
+				CACert: other.CACert,
+				CAKey:  other.CAKey,
+				Cert:   certs.Cert,
+				Key:    certs.Key,
+			},
+			DNSNames:  dnsNames,
+			Now:       time.Now(),
+			ExpectErr: true,
+		},
+		{
+			Name:      "empty",
+			Certs:     &Certs{},
+			DNSNames:  dnsNames,
+			Now:       time.Now(),
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		err := validateCerts(testCase.Certs, testCase.DNSNames, testCase.Now)
+		if testCase.ExpectErr != (err != nil) {
+			t.Errorf("Test case: %s, expected error: %v, got: %v", testCase.Name, testCase.ExpectErr, err)
+		}
+	}
+}
+
+func TestGenerateCertsRotateCA(t *testing.T) {
+	dnsNames := ServiceDNSNames("volcano-system", "volcano-admission-service")
+	current, err := generateCerts(nil, dnsNames, time.Now())
+	if err != nil {
+		t.Fatalf("failed to generate certificates: %v", err)
+	}
+	if !bytes.Equal(current.CABundle(), current.CACert) {
+		t.Errorf("expected CA bundle of the CA only")
+	}
+
+	// The CA is regenerated as its key is invalid, the current CA is kept as the previous one.
+	rotated, err := generateCerts(&Certs{CACert: current.CACert}, dnsNames, time.Now())
+	if err != nil {
+		t.Fatalf("failed to rotate certificates: %v", err)
+	}
+	if bytes.Equal(rotated.CACert, current.CACert) {
+		t.Errorf("expected CA certificate regenerated")
+	}
+	if !bytes.Equal(rotated.PreviousCACert, current.CACert) {
+		t.Errorf("expected current CA certificate kept as the previous one")
+	}
+	if bundle := rotated.CABundle(); !bytes.Contains(bundle, rotated.CACert) || !bytes.Contains(bundle, current.CACert) {
+		t.Errorf("expected CA bundle of both the new and previous CA")
+	}
+
+	// The previous CA is kept with the serving certificate regenerated by the same CA.
+	otherDNSNames := ServiceDNSNames("volcano-system", "other-admission-service")
+	regenerated, err := generateCerts(rotated, otherDNSNames, time.Now())
+	if err != nil {
+		t.Fatalf("failed to regenerate certificates: %v", err)
+	}
+	if !bytes.Equal(regenerated.CACert, rotated.CACert) || !bytes.Equal(regenerated.PreviousCACert, current.CACert) {
+		t.Errorf("expected CA and previous CA certificates kept")
+	}
+
+	// The previous CA is dropped once it expires.
+	expired, err := generateCerts(&Certs{CACert: current.CACert}, dnsNames, time.Now().Add(20*365*24*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to rotate certificates: %v", err)
+	}
+	if len(expired.PreviousCACert) != 0 {
+		t.Errorf("expected expired CA certificate dropped")
+	}
+
+	// The previous CA is stored in the Secret.
+	if stored := dataToCerts(certsToData(rotated)); !bytes.Equal(stored.PreviousCACert, rotated.PreviousCACert) {
+		t.Errorf("expected previous CA certificate stored")
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"strings"
+	"time"
 
 	"k8s.io/api/admissionregistration/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -41,7 +43,22 @@ type Config struct {
 	MutateWebhookName         string
 	ValidateWebhookConfigName string
 	ValidateWebhookName       string
-	PrintVersion              bool
+	// The webhook entries of queues, podgroups and commands in the same webhook configs as jobs
+	MutateQueueWebhookName      string
+	MutatePodGroupWebhookName   string
+	ValidateQueueWebhookName    string
+	ValidatePodGroupWebhookName string
+	ValidateCommandWebhookName  string
+	JobDefaultsConfigMap        string
+	CertReloadPeriod            time.Duration
+	ListenAddress               string
+	GracefulShutdownTimeout     time.Duration
+	ShutdownDelay               time.Duration
+	// The Secret to store the self-managed certificates, and the service of admission server
+	CertSecret     string
+	WebhookService string
+	CapacityCheck  string
+	PrintVersion   bool
 }
 
 // NewConfig create new config
@@ -68,6 +85,37 @@ func (c *Config) AddFlags() {
 		"Name of the mutatingwebhookconfiguration resource in Kubernetes.")
 	flag.StringVar(&c.ValidateWebhookName, "validate-webhook-name", "validatejob.volcano.sh",
 		"Name of the webhook entry in the webhook config.")
+	flag.StringVar(&c.MutateQueueWebhookName, "mutate-queue-webhook-name", "mutatequeue.volcano.sh",
+		"Name of the webhook entry of queues in the mutating webhook config.")
+	flag.StringVar(&c.MutatePodGroupWebhookName, "mutate-podgroup-webhook-name", "mutatepodgroup.volcano.sh",
+		"Name of the webhook entry of podgroups in the mutating webhook config.")
+	flag.StringVar(&c.ValidateQueueWebhookName, "validate-queue-webhook-name", "validatequeue.volcano.sh",
+		"Name of the webhook entry of queues in the validating webhook config.")
+	flag.StringVar(&c.ValidatePodGroupWebhookName, "validate-podgroup-webhook-name", "validatepodgroup.volcano.sh",
+		"Name of the webhook entry of podgroups in the validating webhook config.")
+	flag.StringVar(&c.ValidateCommandWebhookName, "validate-command-webhook-name", "validatecommand.volcano.sh",
+		"Name of the webhook entry of commands in the validating webhook config.")
+	flag.StringVar(&c.JobDefaultsConfigMap, "job-defaults-configmap", "",
+		"The ConfigMap of cluster-wide job defaults in the form of <namespace>/<name>, the defaults are disabled if not set.")
+	flag.DurationVar(&c.CertReloadPeriod, "cert-reload-period", 30*time.Second,
+		"The period to check whether the files of --tls-cert-file and --tls-private-key-file are rotated, "+
+			"or whether the certificates in --cert-secret need to be renewed.")
+	flag.StringVar(&c.ListenAddress, "listen-address", ":8080",
+		"The address to listen on for HTTP requests of health checks and metrics.")
+	flag.DurationVar(&c.GracefulShutdownTimeout, "graceful-shutdown-timeout", 30*time.Second,
+		"The time to wait for the in-flight requests to finish when the server is stopped.")
+	flag.DurationVar(&c.ShutdownDelay, "shutdown-delay", 5*time.Second,
+		"The time to keep serving requests after the server is marked as not ready when it is stopped, "+
+			"so it is removed from the endpoints of webhook service before shutting down.")
+	flag.StringVar(&c.CertSecret, "cert-secret", "",
+		"The Secret in the form of <namespace>/<name> to store the self-signed CA and serving certificate generated "+
+			"by admission server, which also registers the webhook configs of --webhook-service if set.")
+	flag.StringVar(&c.WebhookService, "webhook-service", "volcano-system/volcano-admission-service",
+		"The service of admission server in the form of <namespace>/<name>, used with --cert-secret.")
+	flag.StringVar(&c.CapacityCheck, "capacity-check", "none",
+		"How to handle the jobs whose task does not fit into any node or whose gang exceeds the cluster allocatable, "+
+			"one of none, warn and reject. In warn mode, the jobs are admitted with a warning event of the job, "+
+			"and the audit annotation cluster-capacity-warning which is only in the audit logs of apiserver.")
 	flag.BoolVar(&c.PrintVersion, "version", false, "Show version and quit")
 }
 
@@ -79,9 +127,33 @@ func (c *Config) CheckPortOrDie() error {
 	return nil
 }
 
-// PatchMutateWebhookConfig patches a CA bundle into the specified webhook config.
+// ParseJobDefaultsConfigMap returns the namespace and name of the ConfigMap of job defaults.
+func (c *Config) ParseJobDefaultsConfigMap() (string, string, error) {
+	return parseNamespacedName("job defaults ConfigMap", c.JobDefaultsConfigMap)
+}
+
+// ParseCertSecret returns the namespace and name of the Secret of self-managed certificates.
+func (c *Config) ParseCertSecret() (string, string, error) {
+	return parseNamespacedName("certificate Secret", c.CertSecret)
+}
+
+// ParseWebhookService returns the namespace and name of the service of admission server.
+func (c *Config) ParseWebhookService() (string, string, error) {
+	return parseNamespacedName("webhook service", c.WebhookService)
+}
+
+func parseNamespacedName(kind, value string) (string, string, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", fmt.Errorf("invalid %s %q, expected <namespace>/<name>", kind, value)
+	}
+	return parts[0], parts[1], nil
+}
+
+// PatchMutateWebhookConfig patches a CA bundle into the specified webhook entries of the webhook config,
+// the entries found are patched even if some entries are not found.
 func PatchMutateWebhookConfig(client admissionregistrationv1beta1client.MutatingWebhookConfigurationInterface,
-	webhookConfigName, webhookName string, caBundle []byte) error {
+	webhookConfigName string, webhookNames []string, caBundle []byte) error {
 	config, err := client.Get(webhookConfigName, metav1.GetOptions{})
 	if err != nil {
 		return err
@@ -90,17 +162,19 @@ func PatchMutateWebhookConfig(client admissionregistrationv1beta1client.Mutating
 	if err != nil {
 		return err
 	}
-	found := false
-	for i, w := range config.Webhooks {
-		if w.Name == webhookName {
-			config.Webhooks[i].ClientConfig.CABundle = caBundle[:]
-			found = true
-			break
+	var notFound []string
+	for _, webhookName := range webhookNames {
+		found := false
+		for i, w := range config.Webhooks {
+			if w.Name == webhookName {
+				config.Webhooks[i].ClientConfig.CABundle = caBundle[:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			notFound = append(notFound, webhookName)
 		}
-	}
-	if !found {
-		return apierrors.NewInternalError(fmt.Errorf(
-			"webhook entry %q not found in config %q", webhookName, webhookConfigName))
 	}
 	curr, err := json.Marshal(config)
 	if err != nil {
@@ -112,14 +186,21 @@ func PatchMutateWebhookConfig(client admissionregistrationv1beta1client.Mutating
 	}
 
 	if string(patch) != "{}" {
-		_, err = client.Patch(webhookConfigName, types.StrategicMergePatchType, patch)
+		if _, err = client.Patch(webhookConfigName, types.StrategicMergePatchType, patch); err != nil {
+			return err
+		}
+	}
+	if len(notFound) != 0 {
+		return apierrors.NewInternalError(fmt.Errorf(
+			"webhook entries %q not found in config %q", notFound, webhookConfigName))
 	}
-	return err
+	return nil
 }
 
-// PatchValidateWebhookConfig patches a CA bundle into the specified webhook config.
+// PatchValidateWebhookConfig patches a CA bundle into the specified webhook entries of the webhook config,
+// the entries found are patched even if some entries are not found.
 func PatchValidateWebhookConfig(client admissionregistrationv1beta1client.ValidatingWebhookConfigurationInterface,
-	webhookConfigName, webhookName string, caBundle []byte) error {
+	webhookConfigName string, webhookNames []string, caBundle []byte) error {
 	config, err := client.Get(webhookConfigName, metav1.GetOptions{})
 	if err != nil {
 		return err
@@ -128,17 +209,19 @@ func PatchValidateWebhookConfig(client admissionregistrationv1beta1client.Valida
 	if err != nil {
 		return err
 	}
-	found := false
-	for i, w := range config.Webhooks {
-		if w.Name == webhookName {
-			config.Webhooks[i].ClientConfig.CABundle = caBundle[:]
-			found = true
-			break
+	var notFound []string
+	for _, webhookName := range webhookNames {
+		found := false
+		for i, w := range config.Webhooks {
+			if w.Name == webhookName {
+				config.Webhooks[i].ClientConfig.CABundle = caBundle[:]
+				found = true
+				break
+			}
+		}
+		if !found {
+			notFound = append(notFound, webhookName)
 		}
-	}
-	if !found {
-		return apierrors.NewInternalError(fmt.Errorf(
-			"webhook entry %q not found in config %q", webhookName, webhookConfigName))
 	}
 	curr, err := json.Marshal(config)
 	if err != nil {
@@ -150,7 +233,13 @@ func PatchValidateWebhookConfig(client admissionregistrationv1beta1client.Valida
 	}
 
 	if string(patch) != "{}" {
-		_, err = client.Patch(webhookConfigName, types.StrategicMergePatchType, patch)
+		if _, err = client.Patch(webhookConfigName, types.StrategicMergePatchType, patch); err != nil {
+			return err
+		}
 	}
-	return err
+	if len(notFound) != 0 {
+		return apierrors.NewInternalError(fmt.Errorf(
+			"webhook entries %q not found in config %q", notFound, webhookConfigName))
+	}
+	return nil
 }
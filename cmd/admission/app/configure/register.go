@@ -0,0 +1,154 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configure
+
+import (
+	"github.com/golang/glog"
+
+	"k8s.io/api/admissionregistration/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	admissionregistrationv1beta1client "k8s.io/client-go/kubernetes/typed/admissionregistration/v1beta1"
+	"k8s.io/client-go/util/retry"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	admissioncontroller "volcano.sh/volcano/pkg/admission"
+	batchv1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/volcano/pkg/apis/bus/v1alpha1"
+)
+
+// NewMutateWebhookConfig returns the mutating webhook config of jobs, queues and podgroups, whose
+// webhooks call the admission service with the CA bundle.
+func (c *Config) NewMutateWebhookConfig(serviceNamespace, serviceName string, caBundle []byte) *v1beta1.MutatingWebhookConfiguration {
+	return &v1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.MutateWebhookConfigName,
+		},
+		Webhooks: []v1beta1.Webhook{
+			newWebhook(c.MutateWebhookName, serviceNamespace, serviceName, admissioncontroller.MutateJobPath, caBundle,
+				v1beta1.Ignore, v1beta1.SideEffectClassNone, batchv1alpha1.GroupName, "jobs", v1beta1.Create),
+			newWebhook(c.MutateQueueWebhookName, serviceNamespace, serviceName, admissioncontroller.MutateQueuePath, caBundle,
+				v1beta1.Ignore, v1beta1.SideEffectClassNone, kbv1.GroupName, "queues", v1beta1.Create),
+			newWebhook(c.MutatePodGroupWebhookName, serviceNamespace, serviceName, admissioncontroller.MutatePodGroupPath, caBundle,
+				v1beta1.Ignore, v1beta1.SideEffectClassNone, kbv1.GroupName, "podgroups", v1beta1.Create),
+		},
+	}
+}
+
+// NewValidateWebhookConfig returns the validating webhook config of jobs, queues, podgroups and commands,
+// whose webhooks call the admission service with the CA bundle.
+func (c *Config) NewValidateWebhookConfig(serviceNamespace, serviceName string, caBundle []byte) *v1beta1.ValidatingWebhookConfiguration {
+	return &v1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.ValidateWebhookConfigName,
+		},
+		Webhooks: []v1beta1.Webhook{
+			// The warning events of jobs are only recorded for the requests which are not dry run.
+			newWebhook(c.ValidateWebhookName, serviceNamespace, serviceName, admissioncontroller.AdmitJobPath, caBundle,
+				v1beta1.Ignore, v1beta1.SideEffectClassNoneOnDryRun, batchv1alpha1.GroupName, "jobs", v1beta1.Create, v1beta1.Update),
+			newWebhook(c.ValidateQueueWebhookName, serviceNamespace, serviceName, admissioncontroller.AdmitQueuePath, caBundle,
+				v1beta1.Ignore, v1beta1.SideEffectClassNone, kbv1.GroupName, "queues", v1beta1.Create, v1beta1.Update, v1beta1.Delete),
+			newWebhook(c.ValidatePodGroupWebhookName, serviceNamespace, serviceName, admissioncontroller.AdmitPodGroupPath, caBundle,
+				v1beta1.Ignore, v1beta1.SideEffectClassNone, kbv1.GroupName, "podgroups", v1beta1.Create, v1beta1.Update),
+			// The commands are rejected if they cannot be validated, or the access checks of them would be bypassed.
+			newWebhook(c.ValidateCommandWebhookName, serviceNamespace, serviceName, admissioncontroller.AdmitCommandPath, caBundle,
+				v1beta1.Fail, v1beta1.SideEffectClassNone, busv1alpha1.GroupName, "commands", v1beta1.Create, v1beta1.Update),
+		},
+	}
+}
+
+// newWebhook returns the webhook calling the path of admission service. The webhooks are also called for
+// dry-run requests, as the admit functions have no side effects, or skip them in dry run.
+func newWebhook(name, serviceNamespace, serviceName, path string, caBundle []byte, failurePolicy v1beta1.FailurePolicyType,
+	sideEffects v1beta1.SideEffectClass, group, resource string, operations ...v1beta1.OperationType) v1beta1.Webhook {
+	return v1beta1.Webhook{
+		Name: name,
+		ClientConfig: v1beta1.WebhookClientConfig{
+			Service: &v1beta1.ServiceReference{
+				Namespace: serviceNamespace,
+				Name:      serviceName,
+				Path:      &path,
+			},
+			CABundle: caBundle,
+		},
+		Rules: []v1beta1.RuleWithOperations{
+			{
+				Operations: operations,
+				Rule: v1beta1.Rule{
+					APIGroups:   []string{group},
+					APIVersions: []string{"v1alpha1"},
+					Resources:   []string{resource},
+				},
+			},
+		},
+		FailurePolicy: &failurePolicy,
+		SideEffects:   &sideEffects,
+	}
+}
+
+// RegisterMutateWebhookConfig creates the webhook config, or replaces the webhooks of the existing one.
+func RegisterMutateWebhookConfig(client admissionregistrationv1beta1client.MutatingWebhookConfigurationInterface,
+	config *v1beta1.MutatingWebhookConfiguration) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := client.Get(config.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			if _, err = client.Create(config); err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					// Created by another replica, retry to update it.
+					return apierrors.NewConflict(v1beta1.Resource("mutatingwebhookconfigurations"), config.Name, err)
+				}
+				return err
+			}
+			glog.Infof("Created mutating webhook config %s", config.Name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		current.Webhooks = config.Webhooks
+		_, err = client.Update(current)
+		return err
+	})
+}
+
+// RegisterValidateWebhookConfig creates the webhook config, or replaces the webhooks of the existing one.
+func RegisterValidateWebhookConfig(client admissionregistrationv1beta1client.ValidatingWebhookConfigurationInterface,
+	config *v1beta1.ValidatingWebhookConfiguration) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := client.Get(config.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			if _, err = client.Create(config); err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					// Created by another replica, retry to update it.
+					return apierrors.NewConflict(v1beta1.Resource("validatingwebhookconfigurations"), config.Name, err)
+				}
+				return err
+			}
+			glog.Infof("Created validating webhook config %s", config.Name)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		current.Webhooks = config.Webhooks
+		_, err = client.Update(current)
+		return err
+	})
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configure
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestConfig() *Config {
+	return &Config{
+		MutateWebhookConfigName:     "volcano-mutate-job",
+		MutateWebhookName:           "mutatejob.volcano.sh",
+		MutateQueueWebhookName:      "mutatequeue.volcano.sh",
+		MutatePodGroupWebhookName:   "mutatepodgroup.volcano.sh",
+		ValidateWebhookConfigName:   "volcano-validate-job",
+		ValidateWebhookName:         "validatejob.volcano.sh",
+		ValidateQueueWebhookName:    "validatequeue.volcano.sh",
+		ValidatePodGroupWebhookName: "validatepodgroup.volcano.sh",
+		ValidateCommandWebhookName:  "validatecommand.volcano.sh",
+	}
+}
+
+func TestRegisterMutateWebhookConfig(t *testing.T) {
+	config := newTestConfig()
+	client := fake.NewSimpleClientset().AdmissionregistrationV1beta1().MutatingWebhookConfigurations()
+
+	for _, caBundle := range [][]byte{[]byte("ca-1"), []byte("ca-2")} {
+		if err := RegisterMutateWebhookConfig(client,
+			config.NewMutateWebhookConfig("volcano-system", "volcano-admission-service", caBundle)); err != nil {
+			t.Fatalf("failed to register webhook config: %v", err)
+		}
+
+		registered, err := client.Get(config.MutateWebhookConfigName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get webhook config: %v", err)
+		}
+		if len(registered.Webhooks) != 3 {
+			t.Fatalf("expected 3 webhooks, got %d", len(registered.Webhooks))
+		}
+		for _, webhook := range registered.Webhooks {
+			if !bytes.Equal(webhook.ClientConfig.CABundle, caBundle) {
+				t.Errorf("webhook %s: expected CA bundle %s, got %s", webhook.Name, caBundle, webhook.ClientConfig.CABundle)
+			}
+			if webhook.SideEffects == nil || *webhook.SideEffects != v1beta1.SideEffectClassNone {
+				t.Errorf("webhook %s: expected no side effects, got %v", webhook.Name, webhook.SideEffects)
+			}
+			service := webhook.ClientConfig.Service
+			if service == nil || service.Namespace != "volcano-system" || service.Name != "volcano-admission-service" {
+				t.Errorf("webhook %s: unexpected service %v", webhook.Name, service)
+			}
+		}
+	}
+}
+
+func TestRegisterValidateWebhookConfig(t *testing.T) {
+	config := newTestConfig()
+	client := fake.NewSimpleClientset().AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+
+	// The webhooks of an existing config are replaced.
+	if _, err := client.Create(&v1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: config.ValidateWebhookConfigName,
+		},
+		Webhooks: []v1beta1.Webhook{{Name: "stale.volcano.sh"}},
+	}); err != nil {
+		t.Fatalf("failed to create webhook config: %v", err)
+	}
+
+	if err := RegisterValidateWebhookConfig(client,
+		config.NewValidateWebhookConfig("volcano-system", "volcano-admission-service", []byte("ca"))); err != nil {
+		t.Fatalf("failed to register webhook config: %v", err)
+	}
+
+	registered, err := client.Get(config.ValidateWebhookConfigName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get webhook config: %v", err)
+	}
+	expected := map[string]string{
+		config.ValidateWebhookName:         "/jobs",
+		config.ValidateQueueWebhookName:    "/queues",
+		config.ValidatePodGroupWebhookName: "/podgroups",
+		config.ValidateCommandWebhookName:  "/commands",
+	}
+	if len(registered.Webhooks) != len(expected) {
+		t.Fatalf("expected %d webhooks, got %d", len(expected), len(registered.Webhooks))
+	}
+	for _, webhook := range registered.Webhooks {
+		path, found := expected[webhook.Name]
+		if !found {
+			t.Errorf("unexpected webhook %s", webhook.Name)
+			continue
+		}
+		if webhook.ClientConfig.Service == nil || webhook.ClientConfig.Service.Path == nil ||
+			*webhook.ClientConfig.Service.Path != path {
+			t.Errorf("webhook %s: expected path %s, got %v", webhook.Name, path, webhook.ClientConfig.Service)
+		}
+		sideEffects := v1beta1.SideEffectClassNone
+		if webhook.Name == config.ValidateWebhookName {
+			sideEffects = v1beta1.SideEffectClassNoneOnDryRun
+		}
+		if webhook.SideEffects == nil || *webhook.SideEffects != sideEffects {
+			t.Errorf("webhook %s: expected side effects %s, got %v", webhook.Name, sideEffects, webhook.SideEffects)
+		}
+		failurePolicy := v1beta1.Ignore
+		if webhook.Name == config.ValidateCommandWebhookName {
+			failurePolicy = v1beta1.Fail
+		}
+		if webhook.FailurePolicy == nil || *webhook.FailurePolicy != failurePolicy {
+			t.Errorf("webhook %s: expected failure policy %s, got %v", webhook.Name, failurePolicy, webhook.FailurePolicy)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	//HealthzPath is the path of liveness check
+	HealthzPath = "/healthz"
+	//ReadyzPath is the path of readiness check
+	ReadyzPath = "/readyz"
+	//MetricsPath is the path of Prometheus metrics
+	MetricsPath = "/metrics"
+)
+
+// Readiness reports whether the admission server is ready to serve requests.
+type Readiness struct {
+	ready int32
+}
+
+// Set sets the readiness of server.
+func (r *Readiness) Set(ready bool) {
+	var value int32
+	if ready {
+		value = 1
+	}
+	atomic.StoreInt32(&r.ready, value)
+}
+
+// IsReady returns whether the server is ready.
+func (r *Readiness) IsReady() bool {
+	return atomic.LoadInt32(&r.ready) == 1
+}
+
+// NewHealthHandler returns the handler of health checks and metrics, which is served separately
+// from the webhooks so that it does not need TLS.
+func NewHealthHandler(readiness *Readiness) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthzPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc(ReadyzPath, func(w http.ResponseWriter, r *http.Request) {
+		if !readiness.IsReady() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+	mux.Handle(MetricsPath, promhttp.Handler())
+	return mux
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"k8s.io/api/admission/v1beta1"
+)
+
+const (
+	// VolcanoNamespace - namespace in prometheus used by volcano
+	VolcanoNamespace = "volcano"
+
+	// unknownReason is the reason of denials whose status has no reason
+	unknownReason = "Unknown"
+)
+
+var (
+	admissionRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "admission_requests_total",
+			Help:      "Number of admission requests, by the webhook path, operation and whether the request is allowed",
+		}, []string{"path", "operation", "allowed"},
+	)
+
+	admissionLatency = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "admission_request_latency_milliseconds",
+			Help:      "Admission request latency in milliseconds, by the webhook path",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{"path"},
+	)
+
+	admissionDenials = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: VolcanoNamespace,
+			Name:      "admission_denials_total",
+			Help:      "Number of denied admission requests, by the webhook path and the reason of status",
+		}, []string{"path", "reason"},
+	)
+)
+
+// recordAdmission records the metrics of an admission request.
+func recordAdmission(path string, operation v1beta1.Operation, response *v1beta1.AdmissionResponse, latency time.Duration) {
+	allowed := response != nil && response.Allowed
+	admissionRequests.WithLabelValues(path, string(operation), strconv.FormatBool(allowed)).Inc()
+	admissionLatency.WithLabelValues(path).Observe(float64(latency) / float64(time.Millisecond))
+
+	if !allowed {
+		reason := unknownReason
+		if response != nil && response.Result != nil && len(response.Result.Reason) != 0 {
+			reason = string(response.Result.Reason)
+		}
+		admissionDenials.WithLabelValues(path, reason).Inc()
+	}
+}
@@ -19,8 +19,10 @@ package app
 import (
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
@@ -32,6 +34,7 @@ import (
 
 	appConf "volcano.sh/volcano/cmd/admission/app/configure"
 	admissioncontroller "volcano.sh/volcano/pkg/admission"
+	vkclientset "volcano.sh/volcano/pkg/client/clientset/versioned"
 )
 
 const (
@@ -60,18 +63,29 @@ func GetKubeBatchClient(restConfig *restclient.Config) *versioned.Clientset {
 	return clientset
 }
 
+//GetVolcanoClient get a clientset for volcano
+func GetVolcanoClient(restConfig *restclient.Config) *vkclientset.Clientset {
+	clientset, err := vkclientset.NewForConfig(restConfig)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	return clientset
+}
+
 // ConfigTLS is a helper function that generate tls certificates from directly defined tls config or kubeconfig
 // These are passed in as command line for cluster certification. If tls config is passed in, we use the directly
-// defined tls config, else use that defined in kubeconfig
-func ConfigTLS(config *appConf.Config, restConfig *restclient.Config) *tls.Config {
+// defined tls config, which is reloaded when the files are rotated until stopCh is closed; else use that defined
+// in kubeconfig
+func ConfigTLS(config *appConf.Config, restConfig *restclient.Config, stopCh <-chan struct{}) *tls.Config {
 	if len(config.CertFile) != 0 && len(config.KeyFile) != 0 {
-		sCert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		watcher, err := NewCertWatcher(config.CertFile, config.KeyFile)
 		if err != nil {
 			glog.Fatal(err)
 		}
+		go watcher.Run(config.CertReloadPeriod, stopCh)
 
 		return &tls.Config{
-			Certificates: []tls.Certificate{sCert},
+			GetCertificate: watcher.GetCertificate,
 		}
 	}
 
@@ -90,8 +104,47 @@ func ConfigTLS(config *appConf.Config, restConfig *restclient.Config) *tls.Confi
 	return &tls.Config{}
 }
 
+// ConfigSelfManagedTLS ensures the self-signed certificates in the Secret of --cert-secret, which are shared
+// by all the replicas, and registers the webhook configs calling the service of --webhook-service with the CA
+// bundle. The tls config serving the certificate in the Secret is returned, which follows the certificates
+// renewed in the Secret until stopCh is closed.
+func ConfigSelfManagedTLS(config *appConf.Config, clientset kubernetes.Interface, stopCh <-chan struct{}) (*tls.Config, error) {
+	secretNamespace, secretName, err := config.ParseCertSecret()
+	if err != nil {
+		return nil, err
+	}
+	serviceNamespace, serviceName, err := config.ParseWebhookService()
+	if err != nil {
+		return nil, err
+	}
+
+	registerCABundle := func(caBundle []byte) error {
+		if err := appConf.RegisterMutateWebhookConfig(clientset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations(),
+			config.NewMutateWebhookConfig(serviceNamespace, serviceName, caBundle)); err != nil {
+			return fmt.Errorf("failed to register mutating webhook config: %v", err)
+		}
+		if err := appConf.RegisterValidateWebhookConfig(clientset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations(),
+			config.NewValidateWebhookConfig(serviceNamespace, serviceName, caBundle)); err != nil {
+			return fmt.Errorf("failed to register validating webhook config: %v", err)
+		}
+		return nil
+	}
+
+	watcher, err := NewSecretCertWatcher(clientset, secretNamespace, secretName,
+		appConf.ServiceDNSNames(serviceNamespace, serviceName), registerCABundle)
+	if err != nil {
+		return nil, err
+	}
+	go watcher.Run(config.CertReloadPeriod, stopCh)
+
+	return &tls.Config{
+		GetCertificate: watcher.GetCertificate,
+	}, nil
+}
+
 //Serve the http request
 func Serve(w http.ResponseWriter, r *http.Request, admit admissioncontroller.AdmitFunc) {
+	start := time.Now()
 	var body []byte
 	if r.Body != nil {
 		if data, err := ioutil.ReadAll(r.Body); err == nil {
@@ -116,6 +169,12 @@ func Serve(w http.ResponseWriter, r *http.Request, admit admissioncontroller.Adm
 	}
 	glog.V(3).Infof("sending response: %v", reviewResponse)
 
+	var operation v1beta1.Operation
+	if ar.Request != nil {
+		operation = ar.Request.Operation
+	}
+	recordAdmission(r.URL.Path, operation, reviewResponse, time.Since(start))
+
 	response := createResponse(reviewResponse, &ar)
 	resp, err := json.Marshal(response)
 	if err != nil {
@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHealthHandler(t *testing.T) {
+	readiness := &Readiness{}
+	server := httptest.NewServer(NewHealthHandler(readiness))
+	defer server.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("failed to get %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, _ := get(HealthzPath); code != http.StatusOK {
+		t.Errorf("expect healthz to be %d, but got %d", http.StatusOK, code)
+	}
+	if code, _ := get(ReadyzPath); code != http.StatusServiceUnavailable {
+		t.Errorf("expect readyz to be %d before ready, but got %d", http.StatusServiceUnavailable, code)
+	}
+	readiness.Set(true)
+	if code, _ := get(ReadyzPath); code != http.StatusOK {
+		t.Errorf("expect readyz to be %d after ready, but got %d", http.StatusOK, code)
+	}
+
+	// Serve a denied request, which is recorded in metrics.
+	admit := func(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+		return &v1beta1.AdmissionResponse{
+			Result: &metav1.Status{Reason: metav1.StatusReasonInvalid},
+		}
+	}
+	review, _ := json.Marshal(v1beta1.AdmissionReview{
+		Request: &v1beta1.AdmissionRequest{UID: "uid", Operation: v1beta1.Create},
+	})
+	request := httptest.NewRequest("POST", "/test-path", bytes.NewReader(review))
+	request.Header.Set(CONTENTTYPE, APPLICATIONJSON)
+	Serve(httptest.NewRecorder(), request, admit)
+
+	_, metrics := get(MetricsPath)
+	for _, expect := range []string{
+		`volcano_admission_requests_total{allowed="false",operation="CREATE",path="/test-path"} 1`,
+		`volcano_admission_denials_total{path="/test-path",reason="Invalid"} 1`,
+		`volcano_admission_request_latency_milliseconds_count{path="/test-path"} 1`,
+	} {
+		if !strings.Contains(metrics, expect) {
+			t.Errorf("expect metric %s, but not found", expect)
+		}
+	}
+}
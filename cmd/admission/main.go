@@ -16,18 +16,27 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"flag"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
 
 	"volcano.sh/volcano/cmd/admission/app"
 	appConf "volcano.sh/volcano/cmd/admission/app/configure"
 	admissioncontroller "volcano.sh/volcano/pkg/admission"
 	"volcano.sh/volcano/pkg/version"
 
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
@@ -39,6 +48,46 @@ func serveMutateJobs(w http.ResponseWriter, r *http.Request) {
 	app.Serve(w, r, admissioncontroller.MutateJobs)
 }
 
+func serveQueues(w http.ResponseWriter, r *http.Request) {
+	app.Serve(w, r, admissioncontroller.AdmitQueues)
+}
+
+func serveMutateQueues(w http.ResponseWriter, r *http.Request) {
+	app.Serve(w, r, admissioncontroller.MutateQueues)
+}
+
+func servePodGroups(w http.ResponseWriter, r *http.Request) {
+	app.Serve(w, r, admissioncontroller.AdmitPodGroups)
+}
+
+func serveMutatePodGroups(w http.ResponseWriter, r *http.Request) {
+	app.Serve(w, r, admissioncontroller.MutatePodGroups)
+}
+
+func serveCommands(w http.ResponseWriter, r *http.Request) {
+	app.Serve(w, r, admissioncontroller.AdmitCommands)
+}
+
+// patchCABundle patches the CA bundle of --ca-cert-file into the webhook configs deployed with admission server.
+func patchCABundle(config *appConf.Config, clientset kubernetes.Interface) {
+	caCertPem, err := ioutil.ReadFile(config.CaCertFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return
+	}
+	if err = appConf.PatchMutateWebhookConfig(clientset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations(),
+		config.MutateWebhookConfigName,
+		[]string{config.MutateWebhookName, config.MutateQueueWebhookName, config.MutatePodGroupWebhookName}, caCertPem); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	if err = appConf.PatchValidateWebhookConfig(clientset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations(),
+		config.ValidateWebhookConfigName,
+		[]string{config.ValidateWebhookName, config.ValidateQueueWebhookName,
+			config.ValidatePodGroupWebhookName, config.ValidateCommandWebhookName}, caCertPem); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+}
+
 func main() {
 	config := appConf.NewConfig()
 	config.AddFlags()
@@ -50,6 +99,11 @@ func main() {
 
 	http.HandleFunc(admissioncontroller.AdmitJobPath, serveJobs)
 	http.HandleFunc(admissioncontroller.MutateJobPath, serveMutateJobs)
+	http.HandleFunc(admissioncontroller.AdmitQueuePath, serveQueues)
+	http.HandleFunc(admissioncontroller.MutateQueuePath, serveMutateQueues)
+	http.HandleFunc(admissioncontroller.AdmitPodGroupPath, servePodGroups)
+	http.HandleFunc(admissioncontroller.MutatePodGroupPath, serveMutatePodGroups)
+	http.HandleFunc(admissioncontroller.AdmitCommandPath, serveCommands)
 
 	if err := config.CheckPortOrDie(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -63,28 +117,93 @@ func main() {
 		os.Exit(1)
 	}
 
+	stopCh := make(chan struct{})
+	readiness := &app.Readiness{}
+	healthServer := &http.Server{
+		Addr:    config.ListenAddress,
+		Handler: app.NewHealthHandler(readiness),
+	}
+	go func() {
+		if err := healthServer.ListenAndServe(); err != http.ErrServerClosed {
+			glog.Fatalf("Health and metrics server failed: %v", err)
+		}
+	}()
+
 	clientset := app.GetClient(restConfig)
 
 	admissioncontroller.KubeBatchClientSet = app.GetKubeBatchClient(restConfig)
-
-	caCertPem, err := ioutil.ReadFile(config.CaCertFile)
-	if err != nil {
+	admissioncontroller.VolcanoClientSet = app.GetVolcanoClient(restConfig)
+	admissioncontroller.KubeClientSet = clientset
+	if admissioncontroller.CapacityCheck, err = admissioncontroller.ParseCapacityCheckMode(config.CapacityCheck); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
-	} else {
-		// patch caBundle in webhook
-		if err = appConf.PatchMutateWebhookConfig(clientset.AdmissionregistrationV1beta1().MutatingWebhookConfigurations(),
-			config.MutateWebhookConfigName, config.MutateWebhookName, caCertPem); err != nil {
+		os.Exit(1)
+	}
+	if err := admissioncontroller.StartInformers(stopCh); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	if len(config.JobDefaultsConfigMap) != 0 {
+		namespace, name, err := config.ParseJobDefaultsConfigMap()
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
 		}
-		if err = appConf.PatchValidateWebhookConfig(clientset.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations(),
-			config.ValidateWebhookConfigName, config.ValidateWebhookName, caCertPem); err != nil {
+		go admissioncontroller.WatchJobDefaults(clientset, namespace, name, stopCh)
+	}
+
+	var tlsConfig *tls.Config
+	if len(config.CertSecret) != 0 {
+		if tlsConfig, err = app.ConfigSelfManagedTLS(config, clientset, stopCh); err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
 		}
+	} else {
+		patchCABundle(config, clientset)
+		tlsConfig = app.ConfigTLS(config, restConfig, stopCh)
 	}
 
 	server := &http.Server{
 		Addr:      addr,
-		TLSConfig: app.ConfigTLS(config, restConfig),
+		TLSConfig: tlsConfig,
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+
+		signalCh := make(chan os.Signal, 1)
+		signal.Notify(signalCh, syscall.SIGTERM, syscall.SIGINT)
+		sig := <-signalCh
+
+		// Fail the readiness probe, and keep serving until the server is removed from the endpoints
+		// of webhook service, so the requests sent before the removal are not refused.
+		glog.Infof("Received signal %v, shutting down admission server in %v", sig, config.ShutdownDelay)
+		readiness.Set(false)
+		time.Sleep(config.ShutdownDelay)
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.GracefulShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			glog.Errorf("Failed to shutdown admission server gracefully: %v", err)
+		}
+		if err := healthServer.Shutdown(ctx); err != nil {
+			glog.Errorf("Failed to shutdown health and metrics server gracefully: %v", err)
+		}
+		// The informers are stopped after the in-flight requests finished.
+		close(stopCh)
+	}()
+
+	readiness.Set(true)
+	if err := server.ServeTLS(listener, "", ""); err != http.ErrServerClosed {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
 	}
-	server.ListenAndServeTLS("", "")
+	<-shutdownDone
+	glog.Flush()
 }
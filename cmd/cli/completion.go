@@ -0,0 +1,164 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	jobNameCompletion   = "__vkctl_get_job"
+	taskNameCompletion  = "__vkctl_get_task"
+	queueNameCompletion = "__vkctl_get_queue"
+)
+
+// bashCompletionFunction is the custom functions of bash completion, which complete the names of
+// jobs, tasks and queues by querying apiserver with the connection flags typed in the command line.
+const bashCompletionFunction = `
+__vkctl_flag_value()
+{
+    local name value
+    for name in "$@"; do
+        value=${flaghash[${name}]:-${flaghash[${name}=]}}
+        if [[ -n ${value} ]]; then
+            echo "${value}"
+            return
+        fi
+    done
+}
+
+__vkctl_override_flags()
+{
+    local name value i
+    for name in --master:-s --kubeconfig:-k --context --as; do
+        value=$(__vkctl_flag_value ${name//:/ })
+        if [[ -n ${value} ]]; then
+            echo "${name%%:*}=${value}"
+        fi
+    done
+
+    # --as-group can be repeated, but only its last value is kept in flaghash.
+    for ((i = 1; i < ${#words[@]}; i++)); do
+        case ${words[i]} in
+            --as-group=*)
+                echo "${words[i]}"
+                ;;
+            --as-group)
+                if [[ -n ${words[i+1]} ]]; then
+                    echo "--as-group=${words[i+1]}"
+                fi
+                ;;
+        esac
+    done
+}
+
+__vkctl_compreply()
+{
+    local out
+    if out=$("${words[0]}" "$@" $(__vkctl_override_flags) 2>/dev/null) && [[ ${out} != "Failed to "* ]]; then
+        COMPREPLY=( $( compgen -W "${out[*]}" -- "$cur" ) )
+    fi
+}
+
+__vkctl_get_job()
+{
+    local namespace=$(__vkctl_flag_value --namespace -N)
+    __vkctl_compreply job list ${namespace:+--namespace=${namespace}} -o jsonpath='{.items[*].metadata.name}'
+}
+
+__vkctl_get_task()
+{
+    local namespace=$(__vkctl_flag_value --namespace -N)
+    local name=$(__vkctl_flag_value --name -n)
+    if [[ -n ${name} ]]; then
+        __vkctl_compreply job view ${namespace:+--namespace=${namespace}} --name="${name}" -o jsonpath='{.spec.tasks[*].name}'
+    fi
+}
+
+__vkctl_get_queue()
+{
+    __vkctl_compreply queue list -o jsonpath='{.items[*].metadata.name}'
+}
+`
+
+// completionLong notes that zsh gets no dynamic completion, the custom functions are only run by bash.
+var completionLong = `Output shell completion code for bash or zsh.
+
+The names of jobs, tasks and queues are completed by querying apiserver only in bash;
+zsh completes the commands and flags, but not these names.`
+
+var completionExample = `# Load the completion of vkctl in the current bash shell
+source <(vkctl completion bash)
+
+# Load the completion of vkctl in the current zsh shell, only commands and flags are completed
+source <(vkctl completion zsh)`
+
+func completionCommand() *cobra.Command {
+
+	var command = &cobra.Command{
+		Use:       "completion SHELL",
+		Short:     "Output shell completion code for bash or zsh",
+		Long:      completionLong,
+		Example:   completionExample,
+		ValidArgs: []string{"bash", "zsh"},
+		Args:      cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, runCompletion(cmd.Root(), args[0]))
+		},
+	}
+	return command
+}
+
+func runCompletion(root *cobra.Command, shell string) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletion(os.Stdout)
+	case "zsh":
+		return root.GenZshCompletion(os.Stdout)
+	default:
+		return fmt.Errorf("unsupported shell %q, only bash and zsh are supported", shell)
+	}
+}
+
+// registerCompletions marks the flags of job and queue commands, whose values are names of
+// jobs, tasks or queues, to be completed by the custom functions of bash completion.
+func registerCompletions(jobCmd, queueCmd *cobra.Command) {
+	for _, cmd := range jobCmd.Commands() {
+		// The name of a new job can not be completed.
+		if cmd.Name() != "run" {
+			markFlagCustom(cmd, "name", jobNameCompletion)
+		}
+		markFlagCustom(cmd, "task", taskNameCompletion)
+		markFlagCustom(cmd, "queue", queueNameCompletion)
+	}
+
+	for _, cmd := range queueCmd.Commands() {
+		// The name of a new queue can not be completed.
+		if cmd.Name() != "create" {
+			markFlagCustom(cmd, "name", queueNameCompletion)
+		}
+	}
+}
+
+func markFlagCustom(cmd *cobra.Command, name, function string) {
+	if cmd.Flags().Lookup(name) != nil {
+		cmd.MarkFlagCustom(name, function)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRegisterCompletions(t *testing.T) {
+	rootCmd := buildRootCmd("vkctl")
+
+	testCases := []struct {
+		Command      []string
+		Flag         string
+		ExpectCustom []string
+	}{
+		{Command: []string{"job", "view"}, Flag: "name", ExpectCustom: []string{jobNameCompletion}},
+		{Command: []string{"job", "logs"}, Flag: "task", ExpectCustom: []string{taskNameCompletion}},
+		{Command: []string{"job", "run"}, Flag: "name", ExpectCustom: nil},
+		{Command: []string{"job", "list"}, Flag: "queue", ExpectCustom: []string{queueNameCompletion}},
+		{Command: []string{"queue", "get"}, Flag: "name", ExpectCustom: []string{queueNameCompletion}},
+		{Command: []string{"queue", "create"}, Flag: "name", ExpectCustom: nil},
+	}
+
+	for i, testcase := range testCases {
+		cmd, _, err := rootCmd.Find(testcase.Command)
+		if err != nil {
+			t.Fatalf("case %d: failed to find command %v: %v", i, testcase.Command, err)
+		}
+		flag := cmd.Flags().Lookup(testcase.Flag)
+		if flag == nil {
+			t.Fatalf("case %d: no flag --%s found in command %v", i, testcase.Flag, testcase.Command)
+		}
+		if custom := flag.Annotations[cobra.BashCompCustom]; !reflect.DeepEqual(custom, testcase.ExpectCustom) {
+			t.Errorf("case %d: expected completion %v of flag --%s in command %v, got %v",
+				i, testcase.ExpectCustom, testcase.Flag, testcase.Command, custom)
+		}
+	}
+}
+
+func TestBashOverrideFlags(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skipf("bash is not found: %v", err)
+	}
+
+	var completion bytes.Buffer
+	if err := buildRootCmd("vkctl").GenBashCompletion(&completion); err != nil {
+		t.Fatalf("failed to generate bash completion: %v", err)
+	}
+	file, err := ioutil.TempFile("", "vkctl-completion")
+	if err != nil {
+		t.Fatalf("failed to create completion file: %v", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(completion.Bytes()); err != nil {
+		t.Fatalf("failed to write completion file: %v", err)
+	}
+	file.Close()
+
+	// flaghash and words are set by the generated completion like this when completing the command line.
+	script := `source "$1"
+declare -A flaghash=([--master]=http://apiserver [-k]=/tmp/kubeconfig [--as]=alice [--as-group]=ops)
+words=(vkctl job list --master http://apiserver -k /tmp/kubeconfig --as alice --as-group dev --as-group=ops --name)
+__vkctl_override_flags`
+	out, err := exec.Command(bash, "-c", script, "bash", file.Name()).CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run __vkctl_override_flags: %v, %s", err, out)
+	}
+
+	expected := []string{
+		"--master=http://apiserver",
+		"--kubeconfig=/tmp/kubeconfig",
+		"--as=alice",
+		"--as-group=dev",
+		"--as-group=ops",
+	}
+	if flags := strings.Fields(string(out)); !reflect.DeepEqual(flags, expected) {
+		t.Errorf("expected override flags %v, got %v", expected, flags)
+	}
+}
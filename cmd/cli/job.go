@@ -6,6 +6,12 @@ import (
 	"volcano.sh/volcano/pkg/cli/job"
 )
 
+var jobExecExample = `# Execute ls in the pod with index 3 of task worker in job myjob
+vkctl job exec myjob --task worker --index 3 -- ls`
+
+var jobPortForwardExample = `# Forward the local port 6006 to the first pod of task chief in job myjob
+vkctl job port-forward myjob --task chief 6006`
+
 func buildJobCmd() *cobra.Command {
 	jobCmd := &cobra.Command{
 		Use:   "job",
@@ -14,7 +20,7 @@ func buildJobCmd() *cobra.Command {
 
 	jobRunCmd := &cobra.Command{
 		Use:   "run",
-		Short: "run job by parameters from the command line",
+		Short: "run job by parameters from the command line or a yaml file",
 		Run: func(cmd *cobra.Command, args []string) {
 			checkError(cmd, job.RunJob())
 		},
@@ -62,6 +68,36 @@ func buildJobCmd() *cobra.Command {
 	job.InitResumeFlags(jobResumeCmd)
 	jobCmd.AddCommand(jobResumeCmd)
 
+	jobRestartCmd := &cobra.Command{
+		Use:   "restart",
+		Short: "restart a job, or a task of the job",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, job.RestartJob())
+		},
+	}
+	job.InitRestartFlags(jobRestartCmd)
+	jobCmd.AddCommand(jobRestartCmd)
+
+	jobTerminateCmd := &cobra.Command{
+		Use:   "terminate",
+		Short: "terminate a job",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, job.TerminateJob())
+		},
+	}
+	job.InitTerminateFlags(jobTerminateCmd)
+	jobCmd.AddCommand(jobTerminateCmd)
+
+	jobCompleteCmd := &cobra.Command{
+		Use:   "complete",
+		Short: "complete a job",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, job.CompleteJob())
+		},
+	}
+	job.InitCompleteFlags(jobCompleteCmd)
+	jobCmd.AddCommand(jobCompleteCmd)
+
 	jobDelCmd := &cobra.Command{
 		Use:   "delete",
 		Short: "delete a job ",
@@ -72,5 +108,50 @@ func buildJobCmd() *cobra.Command {
 	job.InitDeleteFlags(jobDelCmd)
 	jobCmd.AddCommand(jobDelCmd)
 
+	jobLogsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "print the logs of job pods",
+		Long: `Print the logs of the pods created in the current version of job. With --previous, the logs of
+the pods left from the last restart of job are printed, e.g. the failed pods retained by the restart;
+--previous-container prints the logs of the previous terminated container of each pod, like kubectl.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, job.LogsJob())
+		},
+	}
+	job.InitLogsFlags(jobLogsCmd)
+	jobCmd.AddCommand(jobLogsCmd)
+
+	jobWatchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "watch job status",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, job.WatchJob())
+		},
+	}
+	job.InitWatchFlags(jobWatchCmd)
+	jobCmd.AddCommand(jobWatchCmd)
+
+	jobExecCmd := &cobra.Command{
+		Use:     "exec JOB -- COMMAND [args...]",
+		Short:   "execute a command in a pod of the task",
+		Example: jobExecExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, job.ExecJob(args, cmd.ArgsLenAtDash()))
+		},
+	}
+	job.InitExecFlags(jobExecCmd)
+	jobCmd.AddCommand(jobExecCmd)
+
+	jobPortForwardCmd := &cobra.Command{
+		Use:     "port-forward JOB [LOCAL_PORT:]REMOTE_PORT...",
+		Short:   "forward local ports to a pod of the task",
+		Example: jobPortForwardExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, job.PortForwardJob(args))
+		},
+	}
+	job.InitPortForwardFlags(jobPortForwardCmd)
+	jobCmd.AddCommand(jobPortForwardCmd)
+
 	return jobCmd
 }
@@ -58,5 +58,35 @@ func buildQueueCmd() *cobra.Command {
 	queue.InitGetFlags(queueGetCmd)
 	jobCmd.AddCommand(queueGetCmd)
 
+	queueUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "updates the weight or capability of a queue",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, queue.UpdateQueue())
+		},
+	}
+	queue.InitUpdateFlags(queueUpdateCmd)
+	jobCmd.AddCommand(queueUpdateCmd)
+
+	queueDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "deletes a queue",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, queue.DeleteQueue())
+		},
+	}
+	queue.InitDeleteFlags(queueDeleteCmd)
+	jobCmd.AddCommand(queueDeleteCmd)
+
+	queueDescribeCmd := &cobra.Command{
+		Use:   "describe",
+		Short: "shows the details of a queue with its jobs and PodGroups",
+		Run: func(cmd *cobra.Command, args []string) {
+			checkError(cmd, queue.DescribeQueue())
+		},
+	}
+	queue.InitDescribeFlags(queueDescribeCmd)
+	jobCmd.AddCommand(queueDescribeCmd)
+
 	return jobCmd
 }
@@ -17,6 +17,9 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
@@ -27,6 +30,8 @@ import (
 	"volcano.sh/volcano/pkg/version"
 )
 
+const kubectlPluginPrefix = "kubectl-"
+
 var logFlushFreq = pflag.Duration("log-flush-frequency", 5*time.Second, "Maximum number of seconds between log flushes")
 
 func main() {
@@ -36,17 +41,38 @@ func main() {
 	go wait.Until(glog.Flush, *logFlushFreq, wait.NeverStop)
 	defer glog.Flush()
 
-	rootCmd := cobra.Command{
-		Use: "vkctl",
+	rootCmd := buildRootCmd(commandName(os.Args[0]))
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Printf("Failed to execute command: %v", err)
+	}
+}
+
+func buildRootCmd(name string) *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:                    name,
+		BashCompletionFunction: bashCompletionFunction,
 	}
 
-	rootCmd.AddCommand(buildJobCmd())
-	rootCmd.AddCommand(buildQueueCmd())
+	jobCmd := buildJobCmd()
+	queueCmd := buildQueueCmd()
+	registerCompletions(jobCmd, queueCmd)
+
+	rootCmd.AddCommand(jobCmd)
+	rootCmd.AddCommand(queueCmd)
 	rootCmd.AddCommand(versionCommand())
+	rootCmd.AddCommand(completionCommand())
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Printf("Failed to execute command: %v", err)
+	return rootCmd
+}
+
+// commandName returns the name of root command; the binary can be installed as a kubectl plugin,
+// e.g. kubectl-vc, which is invoked by "kubectl vc".
+func commandName(binary string) string {
+	name := filepath.Base(binary)
+	if strings.HasPrefix(name, kubectlPluginPrefix) {
+		return name
 	}
+	return "vkctl"
 }
 
 func checkError(cmd *cobra.Command, err error) {
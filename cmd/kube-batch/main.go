@@ -31,6 +31,9 @@ import (
 
 	"github.com/kubernetes-sigs/kube-batch/cmd/kube-batch/app"
 	"github.com/kubernetes-sigs/kube-batch/cmd/kube-batch/app/options"
+
+	// Init volcano scheduler plugins.
+	_ "volcano.sh/volcano/pkg/scheduler/plugins"
 )
 
 var logFlushFreq = pflag.Duration("log-flush-frequency", 5*time.Second, "Maximum number of seconds between log flushes")
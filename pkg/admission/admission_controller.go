@@ -18,19 +18,23 @@ package admission
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/golang/glog"
-	"github.com/hashicorp/go-multierror"
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 
 	"k8s.io/api/admission/v1beta1"
 	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/volcano/pkg/apis/bus/v1alpha1"
 )
 
 const (
@@ -38,11 +42,28 @@ const (
 	AdmitJobPath = "/jobs"
 	//MutateJobPath is the pattern for the mutating jobs
 	MutateJobPath = "/mutating-jobs"
+	//AdmitQueuePath is the pattern for the queues admission
+	AdmitQueuePath = "/queues"
+	//MutateQueuePath is the pattern for the mutating queues
+	MutateQueuePath = "/mutating-queues"
+	//AdmitPodGroupPath is the pattern for the podgroups admission
+	AdmitPodGroupPath = "/podgroups"
+	//MutatePodGroupPath is the pattern for the mutating podgroups
+	MutatePodGroupPath = "/mutating-podgroups"
+	//AdmitCommandPath is the pattern for the commands admission
+	AdmitCommandPath = "/commands"
 )
 
-//The AdmitFunc returns response
+//The AdmitFunc returns response, it must not change the state of cluster for the dry-run requests of
+//AdmissionRequest.DryRun: the webhooks are registered without side effects, or without side effects on
+//dry run if they record events, so they are also called for dry-run requests.
 type AdmitFunc func(v1beta1.AdmissionReview) *v1beta1.AdmissionResponse
 
+// isDryRun returns whether the request is a dry run, whose side effects must be skipped.
+func isDryRun(request *v1beta1.AdmissionRequest) bool {
+	return request.DryRun != nil && *request.DryRun
+}
+
 var scheme = runtime.NewScheme()
 
 //Codecs is for retrieving serializers for the supported wire formats
@@ -109,46 +130,99 @@ func DecodeJob(object runtime.RawExtension, resource metav1.GroupVersionResource
 	return job, nil
 }
 
-func validatePolicies(policies []v1alpha1.LifecyclePolicy, fldPath *field.Path) error {
-	var err error
+//DecodeQueue decodes the queue using deserializer from the raw object
+func DecodeQueue(object runtime.RawExtension, resource metav1.GroupVersionResource) (kbv1.Queue, error) {
+	queueResource := metav1.GroupVersionResource{Group: kbv1.SchemeGroupVersion.Group, Version: kbv1.SchemeGroupVersion.Version, Resource: "queues"}
+	queue := kbv1.Queue{}
+
+	if resource != queueResource {
+		err := fmt.Errorf("expect resource to be %s", queueResource)
+		return queue, err
+	}
+
+	deserializer := Codecs.UniversalDeserializer()
+	if _, _, err := deserializer.Decode(object.Raw, nil, &queue); err != nil {
+		return queue, err
+	}
+	glog.V(3).Infof("the queue struct is %+v", queue)
+
+	return queue, nil
+}
+
+//DecodePodGroup decodes the podgroup using deserializer from the raw object
+func DecodePodGroup(object runtime.RawExtension, resource metav1.GroupVersionResource) (kbv1.PodGroup, error) {
+	podGroupResource := metav1.GroupVersionResource{Group: kbv1.SchemeGroupVersion.Group, Version: kbv1.SchemeGroupVersion.Version, Resource: "podgroups"}
+	podGroup := kbv1.PodGroup{}
+
+	if resource != podGroupResource {
+		err := fmt.Errorf("expect resource to be %s", podGroupResource)
+		return podGroup, err
+	}
+
+	deserializer := Codecs.UniversalDeserializer()
+	if _, _, err := deserializer.Decode(object.Raw, nil, &podGroup); err != nil {
+		return podGroup, err
+	}
+	glog.V(3).Infof("the podgroup struct is %+v", podGroup)
+
+	return podGroup, nil
+}
+
+//DecodeCommand decodes the command using deserializer from the raw object
+func DecodeCommand(object runtime.RawExtension, resource metav1.GroupVersionResource) (busv1alpha1.Command, error) {
+	commandResource := metav1.GroupVersionResource{Group: busv1alpha1.SchemeGroupVersion.Group, Version: busv1alpha1.SchemeGroupVersion.Version, Resource: "commands"}
+	command := busv1alpha1.Command{}
+
+	if resource != commandResource {
+		err := fmt.Errorf("expect resource to be %s", commandResource)
+		return command, err
+	}
+
+	deserializer := Codecs.UniversalDeserializer()
+	if _, _, err := deserializer.Decode(object.Raw, nil, &command); err != nil {
+		return command, err
+	}
+	glog.V(3).Infof("the command struct is %+v", command)
+
+	return command, nil
+}
+
+func validatePolicies(policies []v1alpha1.LifecyclePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
 	policyEvents := map[v1alpha1.Event]struct{}{}
 	exitCodes := map[int32]struct{}{}
 
-	for _, policy := range policies {
+	for index, policy := range policies {
+		policyPath := fldPath.Index(index)
 		if policy.Event != "" && policy.ExitCode != nil {
-			err = multierror.Append(err, fmt.Errorf("must not specify event and exitCode simultaneously"))
-			break
+			allErrs = append(allErrs, field.Forbidden(policyPath, "must not specify event and exitCode simultaneously"))
+			continue
 		}
 
 		if policy.Event == "" && policy.ExitCode == nil {
-			err = multierror.Append(err, fmt.Errorf("either event and exitCode should be specified"))
-			break
+			allErrs = append(allErrs, field.Required(policyPath, "either event and exitCode should be specified"))
+			continue
 		}
 
 		if policy.Event != "" {
 			if allow, ok := policyEventMap[policy.Event]; !ok || !allow {
-				err = multierror.Append(err, field.Invalid(fldPath, policy.Event, fmt.Sprintf("invalid policy event")))
-				break
+				allErrs = append(allErrs, field.NotSupported(policyPath.Child("event"), policy.Event, getValidEvents()))
 			}
 
 			if allow, ok := policyActionMap[policy.Action]; !ok || !allow {
-				err = multierror.Append(err, field.Invalid(fldPath, policy.Action, fmt.Sprintf("invalid policy action")))
-				break
+				allErrs = append(allErrs, field.NotSupported(policyPath.Child("action"), policy.Action, getValidActions()))
 			}
 			if _, found := policyEvents[policy.Event]; found {
-				err = multierror.Append(err, fmt.Errorf("duplicate event %v", policy.Event))
-				break
+				allErrs = append(allErrs, field.Duplicate(policyPath.Child("event"), policy.Event))
 			} else {
 				policyEvents[policy.Event] = struct{}{}
 			}
 		} else {
 			if *policy.ExitCode == 0 {
-				err = multierror.Append(err, fmt.Errorf("0 is not a valid error code"))
-				break
+				allErrs = append(allErrs, field.Invalid(policyPath.Child("exitCode"), *policy.ExitCode, "0 is not a valid error code"))
 			}
 			if _, found := exitCodes[*policy.ExitCode]; found {
-				err = multierror.Append(err, fmt.Errorf("duplicate exitCode %v", *policy.ExitCode))
-				break
+				allErrs = append(allErrs, field.Duplicate(policyPath.Child("exitCode"), *policy.ExitCode))
 			} else {
 				exitCodes[*policy.ExitCode] = struct{}{}
 			}
@@ -156,45 +230,58 @@ func validatePolicies(policies []v1alpha1.LifecyclePolicy, fldPath *field.Path)
 	}
 
 	if _, found := policyEvents[v1alpha1.AnyEvent]; found && len(policyEvents) > 1 {
-		err = multierror.Append(err, fmt.Errorf("if there's * here, no other policy should be here"))
+		allErrs = append(allErrs, field.Forbidden(fldPath, "if there's * here, no other policy should be here"))
 	}
 
-	return err
+	return allErrs
 }
 
-func getValidEvents() []v1alpha1.Event {
-	var events []v1alpha1.Event
+func getValidEvents() []string {
+	var events []string
 	for e, allow := range policyEventMap {
 		if allow {
-			events = append(events, e)
+			events = append(events, string(e))
 		}
 	}
+	sort.Strings(events)
 
 	return events
 }
 
-func getValidActions() []v1alpha1.Action {
-	var actions []v1alpha1.Action
+func getValidActions() []string {
+	var actions []string
 	for a, allow := range policyActionMap {
 		if allow {
-			actions = append(actions, a)
+			actions = append(actions, string(a))
 		}
 	}
+	sort.Strings(actions)
 
 	return actions
 }
 
 // ValidateIO validate IO configuration
-func ValidateIO(volumes []v1alpha1.VolumeSpec) (string, bool) {
+func ValidateIO(volumes []v1alpha1.VolumeSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
 	volumeMap := map[string]bool{}
-	for _, volume := range volumes {
+	for index, volume := range volumes {
+		mountPath := fldPath.Index(index).Child("mountPath")
 		if len(volume.MountPath) == 0 {
-			return " mountPath is required;", true
+			allErrs = append(allErrs, field.Required(mountPath, ""))
+			continue
 		}
 		if _, found := volumeMap[volume.MountPath]; found {
-			return fmt.Sprintf(" duplicated mountPath: %s;", volume.MountPath), true
+			allErrs = append(allErrs, field.Duplicate(mountPath, volume.MountPath))
+			continue
 		}
 		volumeMap[volume.MountPath] = true
 	}
-	return "", false
+	return allErrs
+}
+
+// toInvalidStatus converts the field errors of the object to the status of admission response,
+// which has a cause for each field.
+func toInvalidStatus(kind schema.GroupKind, name string, allErrs field.ErrorList) *metav1.Status {
+	status := apierrors.NewInvalid(kind, name, allErrs).ErrStatus
+	return &status
 }
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package admission
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/volcano/pkg/apis/bus/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
+	"volcano.sh/volcano/pkg/client/clientset/versioned"
+)
+
+//VolcanoClientSet is volcano clientset
+var VolcanoClientSet versioned.Interface
+
+//KubeClientSet is kubernetes clientset
+var KubeClientSet kubernetes.Interface
+
+// AdmitCommands is to admit commands and return response
+func AdmitCommands(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+
+	glog.V(3).Infof("admitting commands -- %s", ar.Request.Operation)
+
+	command, err := DecodeCommand(ar.Request.Object, ar.Request.Resource)
+	if err != nil {
+		return ToAdmissionResponse(err)
+	}
+
+	reviewResponse := v1beta1.AdmissionResponse{}
+	reviewResponse.Allowed = true
+
+	var allErrs field.ErrorList
+	switch ar.Request.Operation {
+	case v1beta1.Create, v1beta1.Update:
+		allErrs = validateCommand(&command, ar.Request.UserInfo)
+	default:
+		err := fmt.Errorf("expect operation to be 'CREATE' or 'UPDATE'")
+		return ToAdmissionResponse(err)
+	}
+
+	if len(allErrs) != 0 {
+		reviewResponse.Allowed = false
+		reviewResponse.Result = toInvalidStatus(helpers.CommandKind.GroupKind(), command.Name, allErrs)
+	}
+	return &reviewResponse
+}
+
+// validateCommand checks the action and target job of the command, and whether the requester
+// is allowed to update the target job, since the command is executed by the controller on behalf of it.
+func validateCommand(command *busv1alpha1.Command, userInfo authenticationv1.UserInfo) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	actionPath := field.NewPath("action")
+	action := v1alpha1.Action(command.Action)
+	if allow, found := policyActionMap[action]; (!found || !allow) && action != v1alpha1.RestartTaskAction {
+		allErrs = append(allErrs, field.NotSupported(actionPath, command.Action,
+			append(getValidActions(), string(v1alpha1.RestartTaskAction))))
+	}
+
+	targetPath := field.NewPath("target")
+	target := command.TargetObject
+	if target == nil {
+		return append(allErrs, field.Required(targetPath, "the target job of command must be set"))
+	}
+	if target.APIVersion != helpers.JobKind.GroupVersion().String() || target.Kind != helpers.JobKind.Kind {
+		return append(allErrs, field.Invalid(targetPath, fmt.Sprintf("%s/%s", target.APIVersion, target.Kind),
+			fmt.Sprintf("only %s is supported", helpers.JobKind)))
+	}
+
+	job, err := VolcanoClientSet.BatchV1alpha1().Jobs(command.Namespace).Get(target.Name, metav1.GetOptions{})
+	if err != nil {
+		return append(allErrs, field.Invalid(targetPath.Child("name"), target.Name, fmt.Sprintf("failed to get job: %v", err)))
+	}
+	if len(target.UID) != 0 && target.UID != job.UID {
+		allErrs = append(allErrs, field.Invalid(targetPath.Child("uid"), target.UID,
+			fmt.Sprintf("job %s/%s has a different uid %s", job.Namespace, job.Name, job.UID)))
+	}
+
+	// The task to restart is specified by the annotation of command.
+	if action == v1alpha1.RestartTaskAction {
+		taskPath := field.NewPath("metadata").Child("annotations").Key(v1alpha1.TaskSpecKey)
+		taskName := command.Annotations[v1alpha1.TaskSpecKey]
+		found := false
+		for _, task := range job.Spec.Tasks {
+			if task.Name == taskName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			allErrs = append(allErrs, field.Invalid(taskPath, taskName,
+				fmt.Sprintf("no task %s found in job %s/%s", taskName, job.Namespace, job.Name)))
+		}
+	}
+
+	if err := authorizeJobUpdate(job, userInfo); err != nil {
+		allErrs = append(allErrs, field.Forbidden(targetPath, err.Error()))
+	}
+
+	return allErrs
+}
+
+// authorizeJobUpdate checks whether the user is allowed to update the job by SubjectAccessReview.
+func authorizeJobUpdate(job *v1alpha1.Job, userInfo authenticationv1.UserInfo) error {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range userInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: job.Namespace,
+				Verb:      "update",
+				Group:     v1alpha1.SchemeGroupVersion.Group,
+				Version:   v1alpha1.SchemeGroupVersion.Version,
+				Resource:  "jobs",
+				Name:      job.Name,
+			},
+			User:   userInfo.Username,
+			Groups: userInfo.Groups,
+			Extra:  extra,
+			UID:    userInfo.UID,
+		},
+	}
+
+	result, err := KubeClientSet.AuthorizationV1().SubjectAccessReviews().Create(review)
+	if err != nil {
+		return fmt.Errorf("failed to review access of user %s: %v", userInfo.Username, err)
+	}
+	if !result.Status.Allowed {
+		return fmt.Errorf("user %s is not allowed to update job %s/%s: %s",
+			userInfo.Username, job.Namespace, job.Name, result.Status.Reason)
+	}
+
+	return nil
+}
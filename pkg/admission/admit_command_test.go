@@ -0,0 +1,184 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package admission
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/volcano/pkg/apis/bus/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
+	volcanoclient "volcano.sh/volcano/pkg/client/clientset/versioned/fake"
+)
+
+func TestAdmitCommands(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job",
+			Namespace: "test",
+			UID:       "job-uid",
+		},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{{Name: "worker"}},
+		},
+	}
+	VolcanoClientSet = volcanoclient.NewSimpleClientset()
+	if _, err := VolcanoClientSet.BatchV1alpha1().Jobs(job.Namespace).Create(job); err != nil {
+		t.Fatalf("Job Creation Failed: %v", err)
+	}
+
+	kubeClient := kubeclient.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "admin" && attrs.Verb == "update" &&
+			attrs.Group == v1alpha1.SchemeGroupVersion.Group && attrs.Resource == "jobs" &&
+			attrs.Namespace == "test" && attrs.Name == "job"
+		if !review.Status.Allowed {
+			review.Status.Reason = "no RBAC policy matched"
+		}
+		return true, review, nil
+	})
+	KubeClientSet = kubeClient
+
+	newCommand := func(action string, target *metav1.OwnerReference) *busv1alpha1.Command {
+		return &busv1alpha1.Command{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "command",
+				Namespace: "test",
+			},
+			Action:       action,
+			TargetObject: target,
+		}
+	}
+
+	newTaskCommand := func(task string) *busv1alpha1.Command {
+		command := newCommand(string(v1alpha1.RestartTaskAction), metav1.NewControllerRef(job, helpers.JobKind))
+		command.Annotations = map[string]string{v1alpha1.TaskSpecKey: task}
+		return command
+	}
+
+	otherJob := job.DeepCopy()
+	otherJob.UID = "other-uid"
+	missingJob := job.DeepCopy()
+	missingJob.Name = "missing"
+
+	testCases := []struct {
+		Name      string
+		Command   *busv1alpha1.Command
+		User      string
+		ExpectErr string
+	}{
+		{
+			Name:    "valid command",
+			Command: newCommand(string(v1alpha1.AbortJobAction), metav1.NewControllerRef(job, helpers.JobKind)),
+			User:    "admin",
+		},
+		{
+			Name:      "unknown action",
+			Command:   newCommand("DestroyJob", metav1.NewControllerRef(job, helpers.JobKind)),
+			User:      "admin",
+			ExpectErr: "action: Unsupported value: \"DestroyJob\"",
+		},
+		{
+			Name:      "internal action",
+			Command:   newCommand(string(v1alpha1.SyncJobAction), metav1.NewControllerRef(job, helpers.JobKind)),
+			User:      "admin",
+			ExpectErr: "action: Unsupported value: \"SyncJob\"",
+		},
+		{
+			Name:    "restart task",
+			Command: newTaskCommand("worker"),
+			User:    "admin",
+		},
+		{
+			Name:      "restart unknown task",
+			Command:   newTaskCommand("ps"),
+			User:      "admin",
+			ExpectErr: "metadata.annotations[volcano.sh/task-spec]: Invalid value: \"ps\"",
+		},
+		{
+			Name:      "no target",
+			Command:   newCommand(string(v1alpha1.AbortJobAction), nil),
+			User:      "admin",
+			ExpectErr: "target: Required value",
+		},
+		{
+			Name: "target is not job",
+			Command: newCommand(string(v1alpha1.AbortJobAction), &metav1.OwnerReference{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       "job",
+			}),
+			User:      "admin",
+			ExpectErr: "target: Invalid value: \"v1/Pod\"",
+		},
+		{
+			Name:      "target job not found",
+			Command:   newCommand(string(v1alpha1.AbortJobAction), metav1.NewControllerRef(missingJob, helpers.JobKind)),
+			User:      "admin",
+			ExpectErr: "target.name: Invalid value: \"missing\"",
+		},
+		{
+			Name:      "target job uid mismatch",
+			Command:   newCommand(string(v1alpha1.AbortJobAction), metav1.NewControllerRef(otherJob, helpers.JobKind)),
+			User:      "admin",
+			ExpectErr: "target.uid: Invalid value: \"other-uid\"",
+		},
+		{
+			Name:      "user not allowed",
+			Command:   newCommand(string(v1alpha1.RestartJobAction), metav1.NewControllerRef(job, helpers.JobKind)),
+			User:      "guest",
+			ExpectErr: "user guest is not allowed to update job test/job: no RBAC policy matched",
+		},
+	}
+
+	for _, testCase := range testCases {
+		raw, err := json.Marshal(testCase.Command)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal command: %v", testCase.Name, err)
+		}
+
+		response := AdmitCommands(v1beta1.AdmissionReview{
+			Request: &v1beta1.AdmissionRequest{
+				Operation: v1beta1.Create,
+				Resource: metav1.GroupVersionResource{
+					Group:    busv1alpha1.SchemeGroupVersion.Group,
+					Version:  busv1alpha1.SchemeGroupVersion.Version,
+					Resource: "commands",
+				},
+				Object:   runtime.RawExtension{Raw: raw},
+				UserInfo: authenticationv1.UserInfo{Username: testCase.User},
+			},
+		})
+		if testCase.ExpectErr == "" && !response.Allowed {
+			t.Errorf("%s: expect command to be allowed, but got %v", testCase.Name, response.Result)
+		}
+		if testCase.ExpectErr != "" && (response.Allowed || !strings.Contains(response.Result.Message, testCase.ExpectErr)) {
+			t.Errorf("%s: expect error %q, but got %v", testCase.Name, testCase.ExpectErr, response.Result)
+		}
+	}
+}
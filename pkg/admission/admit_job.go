@@ -18,13 +18,17 @@ package admission
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/golang/glog"
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 	"github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
 
 	"k8s.io/api/admission/v1beta1"
 	"k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -33,12 +37,21 @@ import (
 	k8scorevalid "k8s.io/kubernetes/pkg/apis/core/validation"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/plugins"
+	pluginsinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
 )
 
 //KubeBatchClientSet is kube-batch clientset
 var KubeBatchClientSet versioned.Interface
 
+const (
+	// QueueCapabilityWarning is the audit annotation key set when the job requests more resources than its queue capability
+	QueueCapabilityWarning = "queue-capability-warning"
+	// ExceedQueueCapabilityReason is the reason of the warning event of the job requesting more resources than its queue capability
+	ExceedQueueCapabilityReason = "ExceedQueueCapability"
+)
+
 // AdmitJobs is to admit jobs and return response
 func AdmitJobs(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
 
@@ -48,121 +61,287 @@ func AdmitJobs(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
 	if err != nil {
 		return ToAdmissionResponse(err)
 	}
-	var msg string
+	var allErrs field.ErrorList
 	reviewResponse := v1beta1.AdmissionResponse{}
 	reviewResponse.Allowed = true
 
 	switch ar.Request.Operation {
 	case v1beta1.Create:
-		msg = validateJob(job, &reviewResponse)
+		allErrs = validateJob(job, &reviewResponse)
+		// Recording events changes the state of cluster, which is not allowed in dry run.
+		if len(allErrs) == 0 && !isDryRun(ar.Request) {
+			recordQueueCapabilityWarning(&job, &reviewResponse)
+			recordClusterCapacityWarning(&job, &reviewResponse)
+		}
 		break
 	case v1beta1.Update:
-		_, err := DecodeJob(ar.Request.OldObject, ar.Request.Resource)
+		oldJob, err := DecodeJob(ar.Request.OldObject, ar.Request.Resource)
 		if err != nil {
 			return ToAdmissionResponse(err)
 		}
+		allErrs = validateJobUpdate(&oldJob, &job)
 		break
 	default:
 		err := fmt.Errorf("expect operation to be 'CREATE' or 'UPDATE'")
 		return ToAdmissionResponse(err)
 	}
 
-	if !reviewResponse.Allowed {
-		reviewResponse.Result = &metav1.Status{Message: strings.TrimSpace(msg)}
+	if len(allErrs) != 0 {
+		reviewResponse.Allowed = false
+		reviewResponse.Result = toInvalidStatus(helpers.JobKind.GroupKind(), job.Name, allErrs)
 	}
 	return &reviewResponse
 }
 
-func validateJob(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) string {
+func validateJob(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) field.ErrorList {
+
+	allErrs := ValidateJobSpec(job)
+	queuePath := field.NewPath("spec").Child("queue")
+
+	// Check whether Queue already present or not
+	queue, err := KubeBatchClientSet.SchedulingV1alpha1().Queues().Get(job.Spec.Queue, metav1.GetOptions{})
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(queuePath, job.Spec.Queue, fmt.Sprintf("failed to get queue: %v", err)))
+	} else {
+		if limit, found := helpers.GetQueueJobLimit(queue, v1alpha1.QueueMaxPendingJobsKey); found {
+			if pending, err := countPendingJobs(queue.Name); err != nil {
+				allErrs = append(allErrs, field.InternalError(queuePath, fmt.Errorf("failed to count pending jobs: %v", err)))
+			} else if pending >= limit {
+				allErrs = append(allErrs, field.Forbidden(queuePath,
+					fmt.Sprintf("queue %s has reached the limit of %d pending jobs", queue.Name, limit)))
+			}
+		}
+		allErrs = append(allErrs, validateQueueCapability(job, queue, reviewResponse)...)
+	}
+	allErrs = append(allErrs, validateClusterCapacity(job, reviewResponse)...)
+
+	if len(allErrs) != 0 {
+		reviewResponse.Allowed = false
+	}
+
+	return allErrs
+}
+
+// ValidateJobSpec validates the job without looking up the cluster, so it can also be used
+// by clients before submitting the job; all errors are reported with their field paths.
+func ValidateJobSpec(job v1alpha1.Job) field.ErrorList {
 
-	var msg string
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
 	taskNames := map[string]string{}
 	var totalReplicas int32
 
 	if job.Spec.MinAvailable < 0 {
-		reviewResponse.Allowed = false
-		return fmt.Sprintf("'minAvailable' cannot be less than zero.")
+		allErrs = append(allErrs, field.Invalid(specPath.Child("minAvailable"), job.Spec.MinAvailable, "must not be less than zero"))
 	}
 
 	if job.Spec.MaxRetry < 0 {
-		reviewResponse.Allowed = false
-		return fmt.Sprintf("'maxRetry' cannot be less than zero.")
+		allErrs = append(allErrs, field.Invalid(specPath.Child("maxRetry"), job.Spec.MaxRetry, "must not be less than zero"))
 	}
 
-	if job.Spec.TTLSecondsAfterFinished != nil && *job.Spec.TTLSecondsAfterFinished < 0 {
-		reviewResponse.Allowed = false
-		return fmt.Sprintf("'ttlSecondsAfterFinished' cannot be less than zero.")
+	if ttl := job.Spec.TTLSecondsAfterFinished; ttl != nil && *ttl < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("ttlSecondsAfterFinished"), *ttl, "must not be less than zero"))
 	}
 
+	tasksPath := specPath.Child("tasks")
 	if len(job.Spec.Tasks) == 0 {
-		reviewResponse.Allowed = false
-		return fmt.Sprintf("No task specified in job spec")
+		allErrs = append(allErrs, field.Required(tasksPath, "no task specified in job spec"))
 	}
 
 	for index, task := range job.Spec.Tasks {
+		taskPath := tasksPath.Index(index)
 		if task.Replicas <= 0 {
-			msg = msg + fmt.Sprintf(" 'replicas' is not set positive in task: %s;", task.Name)
+			allErrs = append(allErrs, field.Invalid(taskPath.Child("replicas"), task.Replicas, "must be greater than zero"))
 		}
 
 		// count replicas
 		totalReplicas = totalReplicas + task.Replicas
 
 		// validate task name
-		if errMsgs := validation.IsDNS1123Label(task.Name); len(errMsgs) > 0 {
-			msg = msg + fmt.Sprintf(" %v;", errMsgs)
+		for _, msg := range validation.IsDNS1123Label(task.Name) {
+			allErrs = append(allErrs, field.Invalid(taskPath.Child("name"), task.Name, msg))
 		}
 
 		// duplicate task name
 		if _, found := taskNames[task.Name]; found {
-			msg = msg + fmt.Sprintf(" duplicated task name %s;", task.Name)
-			break
+			allErrs = append(allErrs, field.Duplicate(taskPath.Child("name"), task.Name))
 		} else {
 			taskNames[task.Name] = task.Name
 		}
 
-		if err := validatePolicies(task.Policies, field.NewPath("spec.tasks.policies")); err != nil {
-			msg = msg + err.Error() + fmt.Sprintf(" valid events are %v, valid actions are %v",
-				getValidEvents(), getValidActions())
+		allErrs = append(allErrs, validatePolicies(task.Policies, taskPath.Child("policies"))...)
+
+		allErrs = append(allErrs, validateTaskTemplate(task, job, taskPath)...)
+	}
+
+	if len(job.Spec.Tasks) != 0 && totalReplicas < job.Spec.MinAvailable {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("minAvailable"), job.Spec.MinAvailable,
+			fmt.Sprintf("must not be greater than total replicas %d of tasks", totalReplicas)))
+	}
+
+	allErrs = append(allErrs, validatePolicies(job.Spec.Policies, specPath.Child("policies"))...)
+
+	allErrs = append(allErrs, validateJobPlugins(&job, specPath.Child("plugins"))...)
+
+	allErrs = append(allErrs, ValidateIO(job.Spec.Volumes, specPath.Child("volumes"))...)
+
+	return allErrs
+}
+
+// validateJobUpdate validates the update of job: the replicas of tasks can be changed to scale the job,
+// and the policies, maxRetry and ttlSecondsAfterFinished can be changed; other fields of spec are immutable.
+func validateJobUpdate(oldJob, newJob *v1alpha1.Job) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	// The user is set by the mutating webhook on creation, and used by the scheduler to share resources.
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newJob.Annotations[v1alpha1.JobUserKey], oldJob.Annotations[v1alpha1.JobUserKey],
+		field.NewPath("metadata").Child("annotations").Key(v1alpha1.JobUserKey))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newJob.Spec.SchedulerName, oldJob.Spec.SchedulerName, specPath.Child("schedulerName"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newJob.Spec.MinAvailable, oldJob.Spec.MinAvailable, specPath.Child("minAvailable"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newJob.Spec.Queue, oldJob.Spec.Queue, specPath.Child("queue"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newJob.Spec.PriorityClassName, oldJob.Spec.PriorityClassName, specPath.Child("priorityClassName"))...)
+	if !apiequality.Semantic.DeepEqual(newJob.Spec.Plugins, oldJob.Spec.Plugins) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("plugins"), apivalidation.FieldImmutableErrorMsg))
+	}
+	allErrs = append(allErrs, validateVolumesUpdate(oldJob.Spec.Volumes, newJob.Spec.Volumes, specPath.Child("volumes"))...)
+	allErrs = append(allErrs, validateTasksUpdate(oldJob.Spec.Tasks, newJob.Spec.Tasks, newJob.Spec.MinAvailable, specPath.Child("tasks"))...)
+
+	// The plugins are validated again with the changed replicas, e.g. the hostnames of svc plugin.
+	allErrs = append(allErrs, validateJobPlugins(newJob, specPath.Child("plugins"))...)
+	allErrs = append(allErrs, validatePolicies(newJob.Spec.Policies, specPath.Child("policies"))...)
+	if newJob.Spec.MaxRetry < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("maxRetry"), newJob.Spec.MaxRetry, "must not be less than zero"))
+	}
+	if ttl := newJob.Spec.TTLSecondsAfterFinished; ttl != nil && *ttl < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("ttlSecondsAfterFinished"), *ttl, "must not be less than zero"))
+	}
+
+	return allErrs
+}
+
+// validateJobPlugins validates the requirements of job plugins, which would fail the job after its pods start.
+func validateJobPlugins(job *v1alpha1.Job, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	var pluginNames []string
+	for name := range job.Spec.Plugins {
+		pluginNames = append(pluginNames, name)
+	}
+	sort.Strings(pluginNames)
+	for _, name := range pluginNames {
+		pb, found := plugins.GetPluginBuilder(name)
+		if !found {
+			allErrs = append(allErrs, field.NotFound(fldPath.Key(name), name))
+			continue
 		}
+		allErrs = append(allErrs, pb(pluginsinterface.PluginClientset{}, job.Spec.Plugins[name]).ValidateJob(job)...)
+	}
+	return allErrs
+}
 
-		msg += validateTaskTemplate(task, job, index)
+// validateTasksUpdate only allows the replicas and policies of tasks to be changed.
+func validateTasksUpdate(oldTasks, newTasks []v1alpha1.TaskSpec, minAvailable int32, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(newTasks) != len(oldTasks) {
+		return append(allErrs, field.Forbidden(fldPath, "tasks can not be added or removed"))
 	}
 
-	if totalReplicas < job.Spec.MinAvailable {
-		msg = msg + " 'minAvailable' should not be greater than total replicas in tasks;"
+	var totalReplicas int32
+	for index, task := range newTasks {
+		taskPath := fldPath.Index(index)
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(task.Name, oldTasks[index].Name, taskPath.Child("name"))...)
+		if !apiequality.Semantic.DeepEqual(task.Template, oldTasks[index].Template) {
+			allErrs = append(allErrs, field.Forbidden(taskPath.Child("template"), apivalidation.FieldImmutableErrorMsg))
+		}
+		if task.Replicas <= 0 {
+			allErrs = append(allErrs, field.Invalid(taskPath.Child("replicas"), task.Replicas, "must be greater than zero"))
+		}
+		allErrs = append(allErrs, validatePolicies(task.Policies, taskPath.Child("policies"))...)
+		totalReplicas += task.Replicas
 	}
 
-	if err := validatePolicies(job.Spec.Policies, field.NewPath("spec.policies")); err != nil {
-		msg = msg + err.Error() + fmt.Sprintf(" valid events are %v, valid actions are %v;",
-			getValidEvents(), getValidActions())
+	if totalReplicas < minAvailable {
+		allErrs = append(allErrs, field.Invalid(fldPath, totalReplicas,
+			fmt.Sprintf("total replicas of tasks must not be less than minAvailable %d", minAvailable)))
 	}
 
-	// invalid job plugins
-	if len(job.Spec.Plugins) != 0 {
-		for name := range job.Spec.Plugins {
-			if _, found := plugins.GetPluginBuilder(name); !found {
-				msg = msg + fmt.Sprintf(" unable to find job plugin: %s", name)
-			}
+	return allErrs
+}
+
+// validateVolumesUpdate forbids changing volumes, except the volume claim name which is generated by
+// the controller if it is not specified.
+func validateVolumesUpdate(oldVolumes, newVolumes []v1alpha1.VolumeSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(newVolumes) != len(oldVolumes) {
+		return append(allErrs, field.Forbidden(fldPath, "volumes can not be added or removed"))
+	}
+
+	for index, volume := range newVolumes {
+		oldVolume := oldVolumes[index]
+		if len(oldVolume.VolumeClaimName) == 0 {
+			oldVolume.VolumeClaimName = volume.VolumeClaimName
+		}
+		if !apiequality.Semantic.DeepEqual(volume, oldVolume) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(index), apivalidation.FieldImmutableErrorMsg))
 		}
 	}
 
-	if validateInfo, ok := ValidateIO(job.Spec.Volumes); ok {
-		msg = msg + validateInfo
+	return allErrs
+}
+
+// validateQueueCapability rejects the job if its gang can never fit into the capability of the queue,
+// and warns if the whole job does not fit.
+func validateQueueCapability(job v1alpha1.Job, queue *kbv1.Queue, reviewResponse *v1beta1.AdmissionResponse) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(queue.Spec.Capability) == 0 {
+		return allErrs
 	}
 
-	// Check whether Queue already present or not
-	if _, err := KubeBatchClientSet.SchedulingV1alpha1().Queues().Get(job.Spec.Queue, metav1.GetOptions{}); err != nil {
-		msg = msg + fmt.Sprintf("Job not created with error: %v", err)
+	if exceeded := exceededResources(helpers.GetJobMinRequests(&job), queue.Spec.Capability); len(exceeded) != 0 {
+		return append(allErrs, field.Forbidden(field.NewPath("spec").Child("tasks"),
+			fmt.Sprintf("minimum resource requests of job exceed the capability of queue %s: %s",
+				queue.Name, strings.Join(exceeded, ", "))))
 	}
 
-	if msg != "" {
-		reviewResponse.Allowed = false
+	if exceeded := exceededResources(helpers.GetJobRequests(&job), queue.Spec.Capability); len(exceeded) != 0 {
+		warning := fmt.Sprintf("total resource requests of job exceed the capability of queue %s: %s, "+
+			"not all tasks can run at the same time", queue.Name, strings.Join(exceeded, ", "))
+		glog.Warningf("Job <%s/%s>: %s", job.Namespace, job.Name, warning)
+		if reviewResponse.AuditAnnotations == nil {
+			reviewResponse.AuditAnnotations = map[string]string{}
+		}
+		reviewResponse.AuditAnnotations[QueueCapabilityWarning] = warning
 	}
 
-	return msg
+	return allErrs
 }
 
-func validateTaskTemplate(task v1alpha1.TaskSpec, job v1alpha1.Job, index int) string {
+// recordQueueCapabilityWarning records the warning of validateQueueCapability as an event of the job,
+// the same as recordClusterCapacityWarning.
+func recordQueueCapabilityWarning(job *v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) {
+	warning, found := reviewResponse.AuditAnnotations[QueueCapabilityWarning]
+	if !found || eventRecorder == nil {
+		return
+	}
+	eventRecorder.Event(job, v1.EventTypeWarning, ExceedQueueCapabilityReason, warning)
+}
+
+// exceededResources returns the description of the resources in requests which are greater than capability,
+// the resources not set in capability are not limited.
+func exceededResources(requests, capability v1.ResourceList) []string {
+	var exceeded []string
+	for name, limit := range capability {
+		if request, found := requests[name]; found && request.Cmp(limit) > 0 {
+			exceeded = append(exceeded, fmt.Sprintf("%s requested %s, capability %s", name, request.String(), limit.String()))
+		}
+	}
+	sort.Strings(exceeded)
+	return exceeded
+}
+
+// validateTaskTemplate validates the pod template of task by the rules of kubernetes, the field paths
+// of errors are prefixed with the path of task.
+func validateTaskTemplate(task v1alpha1.TaskSpec, job v1alpha1.Job, fldPath *field.Path) field.ErrorList {
 	var v1PodTemplate v1.PodTemplate
 	v1PodTemplate.Template = *task.Template.DeepCopy()
 	k8scorev1.SetObjectDefaults_PodTemplate(&v1PodTemplate)
@@ -178,13 +357,11 @@ func validateTaskTemplate(task v1alpha1.TaskSpec, job v1alpha1.Job, index int) s
 		Template: coreTemplateSpec,
 	}
 
-	if allErrs := k8scorevalid.ValidatePodTemplate(&corePodTemplate); len(allErrs) > 0 {
-		msg := fmt.Sprintf("spec.task[%d].", index)
-		for index := range allErrs {
-			msg += allErrs[index].Error() + ". "
-		}
-		return msg
+	allErrs := field.ErrorList{}
+	for _, err := range k8scorevalid.ValidatePodTemplate(&corePodTemplate) {
+		err.Field = fldPath.String() + "." + err.Field
+		allErrs = append(allErrs, err)
 	}
 
-	return ""
+	return allErrs
 }
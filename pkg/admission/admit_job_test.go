@@ -17,6 +17,9 @@ limitations under the License.
 package admission
 
 import (
+	"encoding/json"
+	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -24,7 +27,11 @@ import (
 
 	"k8s.io/api/admission/v1beta1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
 
 	kbv1aplha1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 	v1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
@@ -128,7 +135,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            "duplicated task name duplicated-task-1",
+			ret:            "spec.tasks[1].name: Duplicate value: \"duplicated-task-1\"",
 			ExpectErr:      true,
 		},
 		// Duplicated Policy Event
@@ -174,7 +181,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            "duplicate",
+			ret:            "spec.policies[1].event: Duplicate value: \"PodFailed\"",
 			ExpectErr:      true,
 		},
 		// Min Available illegal
@@ -210,7 +217,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            "'minAvailable' should not be greater than total replicas in tasks",
+			ret:            "spec.minAvailable: Invalid value: 2: must not be greater than total replicas 1 of tasks",
 			ExpectErr:      true,
 		},
 		// Job Plugin illegal
@@ -249,7 +256,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            "unable to find job plugin: big_plugin",
+			ret:            "spec.plugins[big_plugin]: Not found: \"big_plugin\"",
 			ExpectErr:      true,
 		},
 		// ttl-illegal
@@ -286,7 +293,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            "'ttlSecondsAfterFinished' cannot be less than zero",
+			ret:            "spec.ttlSecondsAfterFinished: Invalid value: -1: must not be less than zero",
 			ExpectErr:      true,
 		},
 		// min-MinAvailable less than zero
@@ -322,7 +329,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: false},
-			ret:            "'minAvailable' cannot be less than zero.",
+			ret:            "spec.minAvailable: Invalid value: -1: must not be less than zero",
 			ExpectErr:      true,
 		},
 		// maxretry less than zero
@@ -359,7 +366,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: false},
-			ret:            "'maxRetry' cannot be less than zero.",
+			ret:            "spec.maxRetry: Invalid value: -1: must not be less than zero",
 			ExpectErr:      true,
 		},
 		// no task specified in the job
@@ -377,7 +384,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: false},
-			ret:            "No task specified in job spec",
+			ret:            "spec.tasks: Required value: no task specified in job spec",
 			ExpectErr:      true,
 		},
 		// replica set less than zero
@@ -413,7 +420,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: false},
-			ret:            "'replicas' is not set positive in task: task-1;",
+			ret:            "spec.tasks[0].replicas: Invalid value: -1: must be greater than zero",
 			ExpectErr:      true,
 		},
 		// task name error
@@ -449,9 +456,9 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: false},
-			ret: "[a DNS-1123 label must consist of lower case alphanumeric characters or '-', and " +
-				"must start and end with an alphanumeric character (e.g. 'my-name',  " +
-				"or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')];",
+			ret: "spec.tasks[0].name: Invalid value: \"Task-1\": a DNS-1123 label must consist of lower case alphanumeric " +
+				"characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  " +
+				"or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')",
 			ExpectErr: true,
 		},
 		// Policy Event with exit code
@@ -577,7 +584,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            "invalid policy event",
+			ret:            "spec.policies[0].event: Unsupported value: \"someFakeEvent\"",
 			ExpectErr:      true,
 		},
 		// invalid policy action
@@ -619,7 +626,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            "invalid policy action",
+			ret:            "spec.policies[0].action: Unsupported value: \"someFakeAction\"",
 			ExpectErr:      true,
 		},
 		// policy exit-code zero
@@ -711,7 +718,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            "duplicate exitCode 1",
+			ret:            "spec.policies[1].exitCode: Duplicate value: 1",
 			ExpectErr:      true,
 		},
 		// Policy with any event and other events
@@ -804,7 +811,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            " mountPath is required;",
+			ret:            "spec.volumes[0].mountPath: Required value",
 			ExpectErr:      true,
 		},
 		// duplicate mount volume
@@ -854,7 +861,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            " duplicated mountPath: /var;",
+			ret:            "spec.volumes[1].mountPath: Duplicate value: \"/var\"",
 			ExpectErr:      true,
 		},
 		// task Policy with any event and other events
@@ -936,7 +943,7 @@ func TestValidateExecution(t *testing.T) {
 				},
 			},
 			reviewResponse: v1beta1.AdmissionResponse{Allowed: true},
-			ret:            "Job not created with error: ",
+			ret:            "spec.queue: Invalid value: \"jobQueue\": failed to get queue",
 			ExpectErr:      true,
 		},
 	}
@@ -960,7 +967,7 @@ func TestValidateExecution(t *testing.T) {
 			t.Error("Queue Creation Failed")
 		}
 
-		ret := validateJob(testCase.Job, &testCase.reviewResponse)
+		ret := errorsToString(validateJob(testCase.Job, &testCase.reviewResponse))
 		//fmt.Printf("test-case name:%s, ret:%v  testCase.reviewResponse:%v \n", testCase.Name, ret,testCase.reviewResponse)
 		if testCase.ExpectErr == true && ret == "" {
 			t.Errorf("%s: test case Expect error msg :%s, but got nil.", testCase.Name, testCase.ret)
@@ -982,3 +989,621 @@ func TestValidateExecution(t *testing.T) {
 	}
 
 }
+
+func errorsToString(allErrs field.ErrorList) string {
+	if len(allErrs) == 0 {
+		return ""
+	}
+	return allErrs.ToAggregate().Error()
+}
+
+func TestValidateQueuePendingLimit(t *testing.T) {
+	namespace := "test"
+
+	job := v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job-in-limited-queue",
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.JobSpec{
+			MinAvailable: 1,
+			Queue:        "limited",
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "task-1",
+					Replicas: 1,
+					Template: v1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"name": "test"},
+						},
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "fake-name",
+									Image: "busybox:1.24",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	newPodGroup := func(name, queue string, phase kbv1aplha1.PodGroupPhase) *kbv1aplha1.PodGroup {
+		return &kbv1aplha1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: kbv1aplha1.PodGroupSpec{
+				Queue: queue,
+			},
+			Status: kbv1aplha1.PodGroupStatus{
+				Phase: phase,
+			},
+		}
+	}
+	deleting := newPodGroup("deleting", "limited", kbv1aplha1.PodGroupPending)
+	deleting.DeletionTimestamp = &metav1.Time{}
+
+	testCases := []struct {
+		Name      string
+		Limit     string
+		PodGroups []*kbv1aplha1.PodGroup
+		ExpectErr bool
+	}{
+		{
+			Name:  "pending jobs below limit",
+			Limit: "2",
+			PodGroups: []*kbv1aplha1.PodGroup{
+				newPodGroup("pending", "limited", kbv1aplha1.PodGroupPending),
+				newPodGroup("inqueue", "limited", kbv1aplha1.PodGroupInqueue),
+				newPodGroup("running", "limited", kbv1aplha1.PodGroupRunning),
+				newPodGroup("other-queue", "default", kbv1aplha1.PodGroupPending),
+				deleting,
+			},
+			ExpectErr: false,
+		},
+		{
+			Name:  "pending jobs reach limit",
+			Limit: "2",
+			PodGroups: []*kbv1aplha1.PodGroup{
+				newPodGroup("pending", "limited", kbv1aplha1.PodGroupPending),
+				newPodGroup("not-scheduled", "limited", ""),
+			},
+			ExpectErr: true,
+		},
+		{
+			Name:  "invalid limit is ignored",
+			Limit: "two",
+			PodGroups: []*kbv1aplha1.PodGroup{
+				newPodGroup("pending-1", "limited", kbv1aplha1.PodGroupPending),
+				newPodGroup("pending-2", "limited", kbv1aplha1.PodGroupPending),
+			},
+			ExpectErr: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		queue := kbv1aplha1.Queue{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "limited",
+				Annotations: map[string]string{
+					v1alpha1.QueueMaxPendingJobsKey: testCase.Limit,
+				},
+			},
+			Spec: kbv1aplha1.QueueSpec{
+				Weight: 1,
+			},
+		}
+		podGroupIndexer = newTestPodGroupIndexer(testCase.PodGroups...)
+		KubeBatchClientSet = kubebatchclient.NewSimpleClientset()
+		if _, err := KubeBatchClientSet.SchedulingV1alpha1().Queues().Create(&queue); err != nil {
+			t.Error("Queue Creation Failed")
+		}
+
+		reviewResponse := v1beta1.AdmissionResponse{Allowed: true}
+		ret := errorsToString(validateJob(job, &reviewResponse))
+		if testCase.ExpectErr && !strings.Contains(ret, "has reached the limit of 2 pending jobs") {
+			t.Errorf("%s: test case Expect pending limit error, but got %v", testCase.Name, ret)
+		}
+		if !testCase.ExpectErr && ret != "" {
+			t.Errorf("%s: test case Expect no error, but got error %v", testCase.Name, ret)
+		}
+		if testCase.ExpectErr == reviewResponse.Allowed {
+			t.Errorf("%s: test case Expect Allowed as %v but got %v", testCase.Name, !testCase.ExpectErr, reviewResponse.Allowed)
+		}
+	}
+}
+
+func TestValidateQueueCapability(t *testing.T) {
+	namespace := "test"
+
+	newTask := func(name string, replicas int32, cpu string) v1alpha1.TaskSpec {
+		return v1alpha1.TaskSpec{
+			Name:     name,
+			Replicas: replicas,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"name": "test"},
+				},
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:  "fake-name",
+							Image: "busybox:1.24",
+							Resources: v1.ResourceRequirements{
+								Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		Name         string
+		MinAvailable int32
+		Tasks        []v1alpha1.TaskSpec
+		ExpectErr    bool
+		ExpectWarn   bool
+	}{
+		{
+			Name:         "job fits into queue",
+			MinAvailable: 2,
+			Tasks:        []v1alpha1.TaskSpec{newTask("ps", 1, "1"), newTask("worker", 2, "1")},
+			ExpectErr:    false,
+			ExpectWarn:   false,
+		},
+		{
+			Name:         "gang fits but whole job exceeds queue",
+			MinAvailable: 2,
+			Tasks:        []v1alpha1.TaskSpec{newTask("ps", 1, "1"), newTask("worker", 4, "1")},
+			ExpectErr:    false,
+			ExpectWarn:   true,
+		},
+		{
+			Name:         "cheapest pods of gang fit into queue",
+			MinAvailable: 2,
+			Tasks:        []v1alpha1.TaskSpec{newTask("ps", 1, "4"), newTask("worker", 2, "1")},
+			ExpectErr:    false,
+			ExpectWarn:   true,
+		},
+		{
+			Name:         "gang exceeds queue",
+			MinAvailable: 4,
+			Tasks:        []v1alpha1.TaskSpec{newTask("ps", 1, "1"), newTask("worker", 4, "1")},
+			ExpectErr:    true,
+			ExpectWarn:   false,
+		},
+	}
+
+	queue := kbv1aplha1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "limited",
+		},
+		Spec: kbv1aplha1.QueueSpec{
+			Weight:     1,
+			Capability: v1.ResourceList{v1.ResourceCPU: resource.MustParse("3")},
+		},
+	}
+	KubeBatchClientSet = kubebatchclient.NewSimpleClientset()
+	if _, err := KubeBatchClientSet.SchedulingV1alpha1().Queues().Create(&queue); err != nil {
+		t.Error("Queue Creation Failed")
+	}
+	defer func() { eventRecorder = nil }()
+
+	for _, testCase := range testCases {
+		job := v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job-in-limited-queue",
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.JobSpec{
+				MinAvailable: testCase.MinAvailable,
+				Queue:        queue.Name,
+				Tasks:        testCase.Tasks,
+			},
+		}
+
+		recorder := record.NewFakeRecorder(1)
+		eventRecorder = recorder
+		reviewResponse := v1beta1.AdmissionResponse{Allowed: true}
+		ret := errorsToString(validateJob(job, &reviewResponse))
+		if testCase.ExpectErr && !strings.Contains(ret, "exceed the capability of queue limited") {
+			t.Errorf("%s: test case Expect queue capability error, but got %v", testCase.Name, ret)
+		}
+		if !testCase.ExpectErr && ret != "" {
+			t.Errorf("%s: test case Expect no error, but got error %v", testCase.Name, ret)
+		}
+		if _, found := reviewResponse.AuditAnnotations[QueueCapabilityWarning]; found != testCase.ExpectWarn {
+			t.Errorf("%s: test case Expect warning as %v but got %v", testCase.Name, testCase.ExpectWarn, found)
+		}
+
+		recordQueueCapabilityWarning(&job, &reviewResponse)
+		if recorded := len(recorder.Events) != 0; recorded != testCase.ExpectWarn {
+			t.Errorf("%s: test case Expect warning event as %v but got %v", testCase.Name, testCase.ExpectWarn, recorded)
+		} else if recorded {
+			if event := <-recorder.Events; !strings.HasPrefix(event, "Warning "+ExceedQueueCapabilityReason) {
+				t.Errorf("%s: test case Expect warning event of %s, but got %s", testCase.Name, ExceedQueueCapabilityReason, event)
+			}
+		}
+	}
+}
+
+func TestValidateJobUpdate(t *testing.T) {
+	var ttl int32 = 100
+	var invTTL int32 = -1
+
+	oldJob := v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "job",
+			Namespace:   "test",
+			Annotations: map[string]string{v1alpha1.JobUserKey: "alice"},
+		},
+		Spec: v1alpha1.JobSpec{
+			MinAvailable: 2,
+			Queue:        "default",
+			Volumes: []v1alpha1.VolumeSpec{
+				{
+					MountPath: "/var",
+				},
+			},
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "task-1",
+					Replicas: 2,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "fake-name",
+									Image: "busybox:1.24",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		Name         string
+		Update       func(job *v1alpha1.Job)
+		ExpectFields []string
+	}{
+		{
+			Name:   "update nothing",
+			Update: func(job *v1alpha1.Job) {},
+		},
+		{
+			Name: "scale up task and change policies, maxRetry and ttl",
+			Update: func(job *v1alpha1.Job) {
+				job.Spec.Tasks[0].Replicas = 4
+				job.Spec.Tasks[0].Policies = []v1alpha1.LifecyclePolicy{
+					{Event: v1alpha1.PodFailedEvent, Action: v1alpha1.RestartJobAction},
+				}
+				job.Spec.Policies = []v1alpha1.LifecyclePolicy{
+					{Event: v1alpha1.PodEvictedEvent, Action: v1alpha1.AbortJobAction},
+				}
+				job.Spec.MaxRetry = 5
+				job.Spec.TTLSecondsAfterFinished = &ttl
+			},
+		},
+		{
+			Name: "set generated volume claim name",
+			Update: func(job *v1alpha1.Job) {
+				job.Spec.Volumes[0].VolumeClaimName = "job-volume-abcde"
+			},
+		},
+		{
+			Name: "change immutable fields",
+			Update: func(job *v1alpha1.Job) {
+				job.Spec.SchedulerName = "default-scheduler"
+				job.Spec.MinAvailable = 1
+				job.Spec.Queue = "other"
+				job.Spec.Plugins = map[string][]string{"ssh": {}}
+				job.Spec.Volumes[0].MountPath = "/tmp"
+				job.Spec.Tasks[0].Name = "task-2"
+				job.Spec.Tasks[0].Template.Spec.Containers[0].Image = "busybox:latest"
+			},
+			ExpectFields: []string{
+				"spec.schedulerName", "spec.minAvailable", "spec.queue", "spec.plugins",
+				"spec.volumes[0]", "spec.tasks[0].name", "spec.tasks[0].template",
+			},
+		},
+		{
+			Name: "change user",
+			Update: func(job *v1alpha1.Job) {
+				job.Annotations[v1alpha1.JobUserKey] = "bob"
+			},
+			ExpectFields: []string{"metadata.annotations[volcano.sh/user]"},
+		},
+		{
+			Name: "remove user",
+			Update: func(job *v1alpha1.Job) {
+				job.Annotations = nil
+			},
+			ExpectFields: []string{"metadata.annotations[volcano.sh/user]"},
+		},
+		{
+			Name: "add task",
+			Update: func(job *v1alpha1.Job) {
+				job.Spec.Tasks = append(job.Spec.Tasks, *job.Spec.Tasks[0].DeepCopy())
+				job.Spec.Tasks[1].Name = "task-2"
+			},
+			ExpectFields: []string{"spec.tasks"},
+		},
+		{
+			Name: "scale down task below minAvailable",
+			Update: func(job *v1alpha1.Job) {
+				job.Spec.Tasks[0].Replicas = 1
+			},
+			ExpectFields: []string{"spec.tasks"},
+		},
+		{
+			Name: "invalid mutable fields",
+			Update: func(job *v1alpha1.Job) {
+				job.Spec.Tasks[0].Replicas = 0
+				job.Spec.Policies = []v1alpha1.LifecyclePolicy{
+					{Event: v1alpha1.OutOfSyncEvent, Action: v1alpha1.AbortJobAction},
+				}
+				job.Spec.MaxRetry = -1
+				job.Spec.TTLSecondsAfterFinished = &invTTL
+			},
+			ExpectFields: []string{
+				"spec.tasks[0].replicas", "spec.tasks", "spec.policies[0].event", "spec.maxRetry", "spec.ttlSecondsAfterFinished",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		newJob := oldJob.DeepCopy()
+		testCase.Update(newJob)
+
+		allErrs := validateJobUpdate(&oldJob, newJob)
+		var fields []string
+		for _, err := range allErrs {
+			fields = append(fields, err.Field)
+		}
+		if !reflect.DeepEqual(fields, testCase.ExpectFields) {
+			t.Errorf("%s: expect errors of fields %v, but got %v", testCase.Name, testCase.ExpectFields, allErrs)
+		}
+	}
+}
+
+func TestValidateJobUpdatePlugins(t *testing.T) {
+	// The hostname of the last pod "<job>-worker-9" is 63 characters, the longest valid one.
+	oldJob := v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      strings.Repeat("a", 54),
+			Namespace: "test",
+		},
+		Spec: v1alpha1.JobSpec{
+			MinAvailable: 1,
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "worker",
+					Replicas: 10,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{{Name: "fake-name", Image: "busybox:1.24"}},
+						},
+					},
+				},
+			},
+			Plugins: map[string][]string{"svc": {}},
+		},
+	}
+
+	testCases := []struct {
+		Name      string
+		Replicas  int32
+		ExpectErr string
+	}{
+		{
+			Name:     "scale down",
+			Replicas: 5,
+		},
+		{
+			Name:      "scale up beyond the hostname length",
+			Replicas:  11,
+			ExpectErr: "spec.tasks[0].name: Invalid value: \"worker\": invalid hostname",
+		},
+	}
+
+	for _, testCase := range testCases {
+		newJob := oldJob.DeepCopy()
+		newJob.Spec.Tasks[0].Replicas = testCase.Replicas
+
+		ret := errorsToString(validateJobUpdate(&oldJob, newJob))
+		if testCase.ExpectErr == "" && ret != "" {
+			t.Errorf("%s: expect no error, but got %s", testCase.Name, ret)
+		}
+		if testCase.ExpectErr != "" && !strings.Contains(ret, testCase.ExpectErr) {
+			t.Errorf("%s: expect error %s, but got %s", testCase.Name, testCase.ExpectErr, ret)
+		}
+	}
+}
+
+func TestAdmitJobsStatusCauses(t *testing.T) {
+	var ttl int32 = -1
+	job := v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-job",
+			Namespace: "test",
+		},
+		Spec: v1alpha1.JobSpec{
+			MinAvailable:            -1,
+			MaxRetry:                -1,
+			TTLSecondsAfterFinished: &ttl,
+			Queue:                   "default",
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "task-1",
+					Replicas: 1,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "fake-name",
+									Image: "busybox:1.24",
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:     "task-2",
+					Replicas: 0,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "fake-name",
+									Image: "busybox:1.24",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	KubeBatchClientSet = kubebatchclient.NewSimpleClientset()
+	if _, err := KubeBatchClientSet.SchedulingV1alpha1().Queues().Create(&kbv1aplha1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec:       kbv1aplha1.QueueSpec{Weight: 1},
+	}); err != nil {
+		t.Fatalf("Queue Creation Failed: %v", err)
+	}
+
+	raw, err := json.Marshal(job)
+	if err != nil {
+		t.Fatalf("failed to marshal job: %v", err)
+	}
+	response := AdmitJobs(v1beta1.AdmissionReview{
+		Request: &v1beta1.AdmissionRequest{
+			Operation: v1beta1.Create,
+			Resource: metav1.GroupVersionResource{
+				Group:    v1alpha1.SchemeGroupVersion.Group,
+				Version:  v1alpha1.SchemeGroupVersion.Version,
+				Resource: "jobs",
+			},
+			Object: runtime.RawExtension{Raw: raw},
+		},
+	})
+
+	if response.Allowed {
+		t.Fatalf("expect job to be rejected")
+	}
+	if response.Result.Reason != metav1.StatusReasonInvalid || response.Result.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expect status reason %s and code %d, but got %s and %d", metav1.StatusReasonInvalid,
+			http.StatusUnprocessableEntity, response.Result.Reason, response.Result.Code)
+	}
+
+	var fields []string
+	for _, cause := range response.Result.Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	expectFields := []string{"spec.minAvailable", "spec.maxRetry", "spec.ttlSecondsAfterFinished", "spec.tasks[1].replicas"}
+	if !reflect.DeepEqual(fields, expectFields) {
+		t.Errorf("expect causes of fields %v, but got %v", expectFields, fields)
+	}
+}
+
+func TestValidateJobPlugins(t *testing.T) {
+	var nonRootUser int64 = 1000
+
+	newJob := func(name string, plugins map[string][]string, mutate func(spec *v1.PodSpec)) v1alpha1.Job {
+		template := v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "fake-name", Image: "busybox:1.24"}},
+			},
+		}
+		if mutate != nil {
+			mutate(&template.Spec)
+		}
+		return v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+			},
+			Spec: v1alpha1.JobSpec{
+				MinAvailable: 1,
+				Tasks: []v1alpha1.TaskSpec{
+					{
+						Name:     "worker",
+						Replicas: 10,
+						Template: template,
+					},
+				},
+				Plugins: plugins,
+			},
+		}
+	}
+
+	testCases := []struct {
+		Name      string
+		Job       v1alpha1.Job
+		ExpectErr string
+	}{
+		{
+			Name: "valid plugins",
+			Job:  newJob("job", map[string][]string{"ssh": {}, "svc": {}, "env": {}}, nil),
+		},
+		{
+			Name:      "invalid ssh arguments",
+			Job:       newJob("job", map[string][]string{"ssh": {"--no-such-flag"}}, nil),
+			ExpectErr: "spec.plugins[ssh]: Invalid value",
+		},
+		{
+			Name: "ssh with non-root container",
+			Job: newJob("job", map[string][]string{"ssh": {}}, func(spec *v1.PodSpec) {
+				spec.SecurityContext = &v1.PodSecurityContext{RunAsUser: &nonRootUser}
+			}),
+		},
+		{
+			Name: "ssh no-root",
+			Job:  newJob("job", map[string][]string{"ssh": {"--no-root"}}, nil),
+		},
+		{
+			Name: "ssh path mounted by container",
+			Job: newJob("job", map[string][]string{"ssh": {}}, func(spec *v1.PodSpec) {
+				spec.Containers[0].VolumeMounts = []v1.VolumeMount{{Name: "keys", MountPath: "/root/.ssh/"}}
+				spec.Volumes = []v1.Volume{{Name: "keys", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}}
+			}),
+			ExpectErr: "spec.tasks[0].template.spec.containers[0].volumeMounts[0].mountPath: Invalid value: \"/root/.ssh/\": conflicts with the volume mounted by ssh plugin",
+		},
+		{
+			Name:      "svc hostname too long",
+			Job:       newJob(strings.Repeat("a", 55), map[string][]string{"svc": {}}, nil),
+			ExpectErr: "spec.tasks[0].name: Invalid value: \"worker\": invalid hostname",
+		},
+		{
+			Name: "svc hostname set in template",
+			Job: newJob(strings.Repeat("a", 55), map[string][]string{"svc": {}}, func(spec *v1.PodSpec) {
+				spec.Hostname = "worker"
+			}),
+		},
+		{
+			Name:      "svc service name not a DNS-1035 label",
+			Job:       newJob("1job", map[string][]string{"svc": {}}, nil),
+			ExpectErr: "metadata.name: Invalid value: \"1job\": invalid name of service created by svc plugin",
+		},
+	}
+
+	for _, testCase := range testCases {
+		ret := errorsToString(ValidateJobSpec(testCase.Job))
+		if testCase.ExpectErr == "" && ret != "" {
+			t.Errorf("%s: expected no error, but got %s", testCase.Name, ret)
+		}
+		if testCase.ExpectErr != "" && !strings.Contains(ret, testCase.ExpectErr) {
+			t.Errorf("%s: expected error %s, but got %s", testCase.Name, testCase.ExpectErr, ret)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"volcano.sh/volcano/pkg/apis/helpers"
+)
+
+// AdmitPodGroups is to admit podgroups and return response
+func AdmitPodGroups(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+
+	glog.V(3).Infof("admitting podgroups -- %s", ar.Request.Operation)
+
+	podGroup, err := DecodePodGroup(ar.Request.Object, ar.Request.Resource)
+	if err != nil {
+		return ToAdmissionResponse(err)
+	}
+
+	reviewResponse := v1beta1.AdmissionResponse{}
+	reviewResponse.Allowed = true
+
+	var allErrs field.ErrorList
+	switch ar.Request.Operation {
+	case v1beta1.Create:
+		allErrs = validatePodGroup(&podGroup, nil)
+	case v1beta1.Update:
+		oldPodGroup, err := DecodePodGroup(ar.Request.OldObject, ar.Request.Resource)
+		if err != nil {
+			return ToAdmissionResponse(err)
+		}
+		allErrs = validatePodGroup(&podGroup, &oldPodGroup)
+	default:
+		err := fmt.Errorf("expect operation to be 'CREATE' or 'UPDATE'")
+		return ToAdmissionResponse(err)
+	}
+
+	if len(allErrs) != 0 {
+		reviewResponse.Allowed = false
+		reviewResponse.Result = toInvalidStatus(kbv1.SchemeGroupVersion.WithKind("PodGroup").GroupKind(), podGroup.Name, allErrs)
+	}
+	return &reviewResponse
+}
+
+// validatePodGroup validates the podgroup; the minMember and queue are only checked when they are set or changed,
+// so the status updates of scheduler are not rejected by the podgroups created before.
+func validatePodGroup(podGroup, oldPodGroup *kbv1.PodGroup) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	minMember := podGroup.Spec.MinMember
+	if oldPodGroup == nil || oldPodGroup.Spec.MinMember != minMember {
+		if minMember < 0 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("minMember"), minMember, "must not be less than zero"))
+		}
+		// The podgroup of job follows the minAvailable of job, which may be zero.
+		if minMember == 0 && oldPodGroup == nil && !isControlledByJob(podGroup) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("minMember"), minMember, "must be greater than zero"))
+		}
+	}
+
+	queue := podGroup.Spec.Queue
+	if len(queue) != 0 && (oldPodGroup == nil || oldPodGroup.Spec.Queue != queue) {
+		if _, err := KubeBatchClientSet.SchedulingV1alpha1().Queues().Get(queue, metav1.GetOptions{}); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("queue"), queue, fmt.Sprintf("failed to get queue: %v", err)))
+		}
+	}
+
+	return allErrs
+}
+
+// isControlledByJob returns whether the podgroup is created by the job controller for a job.
+func isControlledByJob(podGroup *kbv1.PodGroup) bool {
+	ref := metav1.GetControllerOf(podGroup)
+	return ref != nil && ref.APIVersion == helpers.JobKind.GroupVersion().String() && ref.Kind == helpers.JobKind.Kind
+}
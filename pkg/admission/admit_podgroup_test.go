@@ -0,0 +1,151 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package admission
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	kubebatchclient "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned/fake"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
+)
+
+var podGroupResource = metav1.GroupVersionResource{
+	Group:    kbv1.SchemeGroupVersion.Group,
+	Version:  kbv1.SchemeGroupVersion.Version,
+	Resource: "podgroups",
+}
+
+func TestAdmitPodGroups(t *testing.T) {
+	KubeBatchClientSet = kubebatchclient.NewSimpleClientset()
+	if _, err := KubeBatchClientSet.SchedulingV1alpha1().Queues().Create(&kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec:       kbv1.QueueSpec{Weight: 1},
+	}); err != nil {
+		t.Fatalf("Queue Creation Failed: %v", err)
+	}
+
+	newPodGroup := func(minMember int32, queue string) *kbv1.PodGroup {
+		return &kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "test"},
+			Spec:       kbv1.PodGroupSpec{MinMember: minMember, Queue: queue},
+		}
+	}
+
+	// The podgroup of job with zero minAvailable created by the job controller.
+	job := &v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "test", UID: "job-uid"}}
+	jobPodGroup := newPodGroup(0, "default")
+	jobPodGroup.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(job, helpers.JobKind)}
+	scheduledJobPodGroup := jobPodGroup.DeepCopy()
+	scheduledJobPodGroup.Status.Phase = kbv1.PodGroupRunning
+
+	testCases := []struct {
+		Name        string
+		Operation   v1beta1.Operation
+		PodGroup    *kbv1.PodGroup
+		OldPodGroup *kbv1.PodGroup
+		ExpectErr   string
+	}{
+		{
+			Name:      "valid podgroup",
+			Operation: v1beta1.Create,
+			PodGroup:  newPodGroup(1, "default"),
+		},
+		{
+			Name:      "podgroup without queue",
+			Operation: v1beta1.Create,
+			PodGroup:  newPodGroup(1, ""),
+		},
+		{
+			Name:      "zero minMember",
+			Operation: v1beta1.Create,
+			PodGroup:  newPodGroup(0, "default"),
+			ExpectErr: "spec.minMember: Invalid value: 0",
+		},
+		{
+			Name:      "negative minMember",
+			Operation: v1beta1.Create,
+			PodGroup:  newPodGroup(-1, "default"),
+			ExpectErr: "spec.minMember: Invalid value: -1",
+		},
+		{
+			Name:      "zero minMember of job",
+			Operation: v1beta1.Create,
+			PodGroup:  jobPodGroup,
+		},
+		{
+			Name:        "update status of podgroup with zero minMember",
+			Operation:   v1beta1.Update,
+			PodGroup:    scheduledJobPodGroup,
+			OldPodGroup: jobPodGroup,
+		},
+		{
+			Name:        "update podgroup to negative minMember",
+			Operation:   v1beta1.Update,
+			PodGroup:    newPodGroup(-1, "default"),
+			OldPodGroup: newPodGroup(1, "default"),
+			ExpectErr:   "spec.minMember: Invalid value: -1",
+		},
+		{
+			Name:      "queue not found",
+			Operation: v1beta1.Create,
+			PodGroup:  newPodGroup(1, "missing"),
+			ExpectErr: "spec.queue: Invalid value: \"missing\"",
+		},
+		{
+			Name:        "update podgroup with unchanged queue",
+			Operation:   v1beta1.Update,
+			PodGroup:    newPodGroup(2, "deleted"),
+			OldPodGroup: newPodGroup(1, "deleted"),
+		},
+		{
+			Name:        "update podgroup to missing queue",
+			Operation:   v1beta1.Update,
+			PodGroup:    newPodGroup(1, "missing"),
+			OldPodGroup: newPodGroup(1, "default"),
+			ExpectErr:   "spec.queue: Invalid value: \"missing\"",
+		},
+	}
+
+	for _, testCase := range testCases {
+		request := &v1beta1.AdmissionRequest{
+			Operation: testCase.Operation,
+			Resource:  podGroupResource,
+		}
+		raw, _ := json.Marshal(testCase.PodGroup)
+		request.Object = runtime.RawExtension{Raw: raw}
+		if testCase.OldPodGroup != nil {
+			raw, _ := json.Marshal(testCase.OldPodGroup)
+			request.OldObject = runtime.RawExtension{Raw: raw}
+		}
+
+		response := AdmitPodGroups(v1beta1.AdmissionReview{Request: request})
+		if testCase.ExpectErr == "" && !response.Allowed {
+			t.Errorf("%s: expect podgroup to be allowed, but got %v", testCase.Name, response.Result)
+		}
+		if testCase.ExpectErr != "" && (response.Allowed || !strings.Contains(response.Result.Message, testCase.ExpectErr)) {
+			t.Errorf("%s: expect error %q, but got %v", testCase.Name, testCase.ExpectErr, response.Result)
+		}
+	}
+}
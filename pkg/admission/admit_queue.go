@@ -0,0 +1,108 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// AdmitQueues is to admit queues and return response
+func AdmitQueues(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+
+	glog.V(3).Infof("admitting queues -- %s", ar.Request.Operation)
+
+	reviewResponse := v1beta1.AdmissionResponse{}
+	reviewResponse.Allowed = true
+
+	var allErrs field.ErrorList
+	var name string
+	switch ar.Request.Operation {
+	case v1beta1.Create, v1beta1.Update:
+		queue, err := DecodeQueue(ar.Request.Object, ar.Request.Resource)
+		if err != nil {
+			return ToAdmissionResponse(err)
+		}
+		name = queue.Name
+		allErrs = validateQueue(&queue)
+	case v1beta1.Delete:
+		// The object is not sent in the request of deletion, so the queue is referred by name.
+		if err := validateQueueDeletion(ar.Request.Name); err != nil {
+			reviewResponse.Allowed = false
+			reviewResponse.Result = &metav1.Status{Message: err.Error()}
+		}
+		return &reviewResponse
+	default:
+		err := fmt.Errorf("expect operation to be 'CREATE', 'UPDATE' or 'DELETE'")
+		return ToAdmissionResponse(err)
+	}
+
+	if len(allErrs) != 0 {
+		reviewResponse.Allowed = false
+		reviewResponse.Result = toInvalidStatus(kbv1.SchemeGroupVersion.WithKind("Queue").GroupKind(), name, allErrs)
+	}
+	return &reviewResponse
+}
+
+func validateQueue(queue *kbv1.Queue) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	if queue.Spec.Weight < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("weight"), queue.Spec.Weight, "must not be less than zero"))
+	}
+
+	capabilityPath := specPath.Child("capability")
+	for name, quantity := range queue.Spec.Capability {
+		resourcePath := capabilityPath.Key(string(name))
+		for _, msg := range validation.IsQualifiedName(string(name)) {
+			allErrs = append(allErrs, field.Invalid(resourcePath, name, msg))
+		}
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(resourcePath, quantity.String(), "must not be less than zero"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateQueueDeletion rejects deleting the queue which is still used by podgroups; the podgroups being
+// deleted, e.g. by "vkctl queue delete --force", are ignored.
+func validateQueueDeletion(name string) error {
+	pgs, err := listQueuePodGroups(name)
+	if err != nil {
+		return fmt.Errorf("failed to list podgroups of queue %s: %v", name, err)
+	}
+
+	var inUse []string
+	for _, pg := range pgs {
+		inUse = append(inUse, pg.Namespace+"/"+pg.Name)
+	}
+	if len(inUse) != 0 {
+		return fmt.Errorf("queue %s is in use by podgroups %s", name, strings.Join(inUse, ", "))
+	}
+
+	return nil
+}
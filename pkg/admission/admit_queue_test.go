@@ -0,0 +1,164 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package admission
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	"k8s.io/api/admission/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var queueResource = metav1.GroupVersionResource{
+	Group:    kbv1.SchemeGroupVersion.Group,
+	Version:  kbv1.SchemeGroupVersion.Version,
+	Resource: "queues",
+}
+
+func TestAdmitQueues(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Queue     kbv1.Queue
+		ExpectErr string
+	}{
+		{
+			Name: "valid queue",
+			Queue: kbv1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: kbv1.QueueSpec{
+					Weight:     1,
+					Capability: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+				},
+			},
+		},
+		{
+			Name: "queue without weight",
+			Queue: kbv1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "no-weight"},
+			},
+		},
+		{
+			Name: "negative weight",
+			Queue: kbv1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "negative-weight"},
+				Spec:       kbv1.QueueSpec{Weight: -1},
+			},
+			ExpectErr: "spec.weight: Invalid value: -1",
+		},
+		{
+			Name: "negative capability",
+			Queue: kbv1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "negative-capability"},
+				Spec: kbv1.QueueSpec{
+					Weight:     1,
+					Capability: v1.ResourceList{v1.ResourceMemory: resource.MustParse("-1Gi")},
+				},
+			},
+			ExpectErr: "spec.capability[memory]: Invalid value: \"-1Gi\"",
+		},
+		{
+			Name: "invalid resource name",
+			Queue: kbv1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "invalid-resource"},
+				Spec: kbv1.QueueSpec{
+					Weight:     1,
+					Capability: v1.ResourceList{"invalid/resource/name": resource.MustParse("1")},
+				},
+			},
+			ExpectErr: "spec.capability[invalid/resource/name]",
+		},
+	}
+
+	for _, testCase := range testCases {
+		raw, err := json.Marshal(testCase.Queue)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal queue: %v", testCase.Name, err)
+		}
+
+		response := AdmitQueues(v1beta1.AdmissionReview{
+			Request: &v1beta1.AdmissionRequest{
+				Operation: v1beta1.Create,
+				Resource:  queueResource,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+		if testCase.ExpectErr == "" && !response.Allowed {
+			t.Errorf("%s: expect queue to be allowed, but got %v", testCase.Name, response.Result)
+		}
+		if testCase.ExpectErr != "" && (response.Allowed || !strings.Contains(response.Result.Message, testCase.ExpectErr)) {
+			t.Errorf("%s: expect error %q, but got %v", testCase.Name, testCase.ExpectErr, response.Result)
+		}
+	}
+}
+
+func TestAdmitQueueDeletion(t *testing.T) {
+	deleting := metav1.Now()
+	podGroupIndexer = newTestPodGroupIndexer(
+		&kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "test"},
+			Spec:       kbv1.PodGroupSpec{MinMember: 1, Queue: "used"},
+		},
+		&kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "deleting", DeletionTimestamp: &deleting},
+			Spec:       kbv1.PodGroupSpec{MinMember: 1, Queue: "deleting"},
+		},
+	)
+
+	testCases := []struct {
+		Name        string
+		Queue       string
+		ExpectAllow bool
+	}{
+		{
+			Name:        "delete queue in use",
+			Queue:       "used",
+			ExpectAllow: false,
+		},
+		{
+			Name:        "delete queue whose podgroups are being deleted",
+			Queue:       "deleting",
+			ExpectAllow: true,
+		},
+		{
+			Name:        "delete queue not in use",
+			Queue:       "unused",
+			ExpectAllow: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		response := AdmitQueues(v1beta1.AdmissionReview{
+			Request: &v1beta1.AdmissionRequest{
+				Operation: v1beta1.Delete,
+				Resource:  queueResource,
+				Name:      testCase.Queue,
+			},
+		})
+		if response.Allowed != testCase.ExpectAllow {
+			t.Errorf("%s: expect allowed to be %v, but got %v", testCase.Name, testCase.ExpectAllow, response.Result)
+		}
+		if !response.Allowed && !strings.Contains(response.Result.Message, "test/pg") {
+			t.Errorf("%s: expect podgroup test/pg in message, but got %s", testCase.Name, response.Result.Message)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+
+	"k8s.io/api/admission/v1beta1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
+)
+
+// CapacityCheckMode is how jobs which can never fit into the cluster are handled.
+type CapacityCheckMode string
+
+const (
+	// CapacityCheckNone disables the check of cluster capacity.
+	CapacityCheckNone CapacityCheckMode = "none"
+	// CapacityCheckWarn admits the jobs exceeding cluster capacity with a warning, which is logged, set as
+	// an audit annotation and recorded as a warning event of the job.
+	CapacityCheckWarn CapacityCheckMode = "warn"
+	// CapacityCheckReject rejects the jobs exceeding cluster capacity.
+	CapacityCheckReject CapacityCheckMode = "reject"
+
+	// ClusterCapacityWarning is the audit annotation key set when the job exceeds the cluster capacity in warn mode
+	ClusterCapacityWarning = "cluster-capacity-warning"
+	// ExceedClusterCapacityReason is the reason of the warning event of the job exceeding the cluster capacity in warn mode
+	ExceedClusterCapacityReason = "ExceedClusterCapacity"
+)
+
+// CapacityCheck is the mode of checking job requests against cluster capacity, disabled by default.
+var CapacityCheck = CapacityCheckNone
+
+// ParseCapacityCheckMode parses the mode of checking cluster capacity.
+func ParseCapacityCheckMode(mode string) (CapacityCheckMode, error) {
+	switch m := CapacityCheckMode(mode); m {
+	case CapacityCheckNone, CapacityCheckWarn, CapacityCheckReject:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid capacity check mode %q, expected one of %s, %s, %s",
+			mode, CapacityCheckNone, CapacityCheckWarn, CapacityCheckReject)
+	}
+}
+
+// validateClusterCapacity checks that every pod of the tasks fits into the allocatable of one node,
+// and the gang of job fits into the total allocatable of cluster. Node selectors, taints and the
+// resources used by running pods are not considered, so only the jobs which can never be scheduled
+// are reported. The errors are returned in reject mode, or reported as a warning in warn mode.
+func validateClusterCapacity(job v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if CapacityCheck != CapacityCheckReject && CapacityCheck != CapacityCheckWarn {
+		return allErrs
+	}
+
+	nodes, err := nodeLister.List(labels.Everything())
+	if err != nil {
+		glog.Errorf("Failed to list nodes to check capacity of job <%s/%s>: %v", job.Namespace, job.Name, err)
+		return allErrs
+	}
+
+	var allocatables []v1.ResourceList
+	total := v1.ResourceList{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		allocatables = append(allocatables, node.Status.Allocatable)
+		for name, quantity := range node.Status.Allocatable {
+			value := total[name]
+			value.Add(quantity)
+			total[name] = value
+		}
+	}
+
+	tasksPath := field.NewPath("spec").Child("tasks")
+	for index := range job.Spec.Tasks {
+		task := &job.Spec.Tasks[index]
+		requests := helpers.GetTaskRequests(task)
+		if fitsAnyNode(requests, allocatables) {
+			continue
+		}
+
+		largest := v1.ResourceList{}
+		for _, allocatable := range allocatables {
+			for name, quantity := range allocatable {
+				if value, found := largest[name]; !found || quantity.Cmp(value) > 0 {
+					largest[name] = quantity
+				}
+			}
+		}
+		msg := fmt.Sprintf("requests of task %s do not fit into any node", task.Name)
+		if insufficient := insufficientResources(requests, largest); len(insufficient) != 0 {
+			msg = fmt.Sprintf("%s: %s", msg, strings.Join(insufficient, ", "))
+		}
+		allErrs = append(allErrs, field.Forbidden(tasksPath.Index(index), msg))
+	}
+
+	if insufficient := insufficientResources(helpers.GetJobMinRequests(&job), total); len(insufficient) != 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("minAvailable"),
+			fmt.Sprintf("minimum resource requests of job exceed the allocatable of cluster: %s",
+				strings.Join(insufficient, ", "))))
+	}
+
+	if len(allErrs) == 0 || CapacityCheck == CapacityCheckReject {
+		return allErrs
+	}
+
+	warning := allErrs.ToAggregate().Error()
+	glog.Warningf("Job <%s/%s> exceeds cluster capacity: %s", job.Namespace, job.Name, warning)
+	if reviewResponse.AuditAnnotations == nil {
+		reviewResponse.AuditAnnotations = map[string]string{}
+	}
+	reviewResponse.AuditAnnotations[ClusterCapacityWarning] = warning
+	return field.ErrorList{}
+}
+
+// recordClusterCapacityWarning records the warning of validateClusterCapacity as an event of the job,
+// as the audit annotation is only in the audit logs of apiserver while the event is visible to users,
+// e.g. by `kubectl get events`.
+func recordClusterCapacityWarning(job *v1alpha1.Job, reviewResponse *v1beta1.AdmissionResponse) {
+	warning, found := reviewResponse.AuditAnnotations[ClusterCapacityWarning]
+	if !found || eventRecorder == nil {
+		return
+	}
+	eventRecorder.Event(job, v1.EventTypeWarning, ExceedClusterCapacityReason, warning)
+}
+
+func fitsAnyNode(requests v1.ResourceList, allocatables []v1.ResourceList) bool {
+	for _, allocatable := range allocatables {
+		if len(insufficientResources(requests, allocatable)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// insufficientResources returns the description of the resources in requests which are greater than
+// allocatable, the resources not in allocatable are not available at all.
+func insufficientResources(requests, allocatable v1.ResourceList) []string {
+	var insufficient []string
+	for name, request := range requests {
+		if request.IsZero() {
+			continue
+		}
+		available := allocatable[name]
+		if request.Cmp(available) > 0 {
+			insufficient = append(insufficient, fmt.Sprintf("%s requested %s, allocatable %s",
+				name, request.String(), available.String()))
+		}
+	}
+	sort.Strings(insufficient)
+	return insufficient
+}
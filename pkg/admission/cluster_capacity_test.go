@@ -0,0 +1,175 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/api/admission/v1beta1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestValidateClusterCapacity(t *testing.T) {
+	newNode := func(name, cpu, memory string, unschedulable bool) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.NodeSpec{Unschedulable: unschedulable},
+			Status: v1.NodeStatus{
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse(cpu),
+					v1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+	newTask := func(name string, replicas int32, cpu, memory string) v1alpha1.TaskSpec {
+		return v1alpha1.TaskSpec{
+			Name:     name,
+			Replicas: replicas,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:  "fake-name",
+							Image: "busybox:1.24",
+							Resources: v1.ResourceRequirements{
+								Requests: v1.ResourceList{
+									v1.ResourceCPU:    resource.MustParse(cpu),
+									v1.ResourceMemory: resource.MustParse(memory),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	nodeLister = newTestNodeLister(
+		newNode("node-1", "4", "8Gi", false),
+		newNode("node-2", "8", "4Gi", false),
+		newNode("cordoned", "64", "256Gi", true),
+	)
+	defer func() {
+		CapacityCheck = CapacityCheckNone
+		eventRecorder = nil
+	}()
+
+	testCases := []struct {
+		Name         string
+		Mode         CapacityCheckMode
+		MinAvailable int32
+		Tasks        []v1alpha1.TaskSpec
+		ExpectErr    string
+		ExpectWarn   bool
+	}{
+		{
+			Name:         "job fits into cluster",
+			Mode:         CapacityCheckReject,
+			MinAvailable: 2,
+			Tasks:        []v1alpha1.TaskSpec{newTask("ps", 1, "2", "6Gi"), newTask("worker", 1, "6", "2Gi")},
+		},
+		{
+			Name:         "task exceeds largest node",
+			Mode:         CapacityCheckReject,
+			MinAvailable: 1,
+			Tasks:        []v1alpha1.TaskSpec{newTask("ps", 1, "1", "1Gi"), newTask("worker", 1, "16", "1Gi")},
+			ExpectErr:    "spec.tasks[1]: Forbidden: requests of task worker do not fit into any node: cpu requested 16, allocatable 8",
+		},
+		{
+			Name:         "task fits no single node",
+			Mode:         CapacityCheckReject,
+			MinAvailable: 1,
+			Tasks:        []v1alpha1.TaskSpec{newTask("worker", 1, "6", "6Gi")},
+			ExpectErr:    "spec.tasks[0]: Forbidden: requests of task worker do not fit into any node",
+		},
+		{
+			Name:         "gang exceeds cluster",
+			Mode:         CapacityCheckReject,
+			MinAvailable: 4,
+			Tasks:        []v1alpha1.TaskSpec{newTask("worker", 4, "4", "1Gi")},
+			ExpectErr:    "spec.minAvailable: Forbidden: minimum resource requests of job exceed the allocatable of cluster: cpu requested 16, allocatable 12",
+		},
+		{
+			Name:         "gang exceeds cluster in warn mode",
+			Mode:         CapacityCheckWarn,
+			MinAvailable: 4,
+			Tasks:        []v1alpha1.TaskSpec{newTask("worker", 4, "4", "1Gi")},
+			ExpectWarn:   true,
+		},
+		{
+			Name:         "check disabled",
+			Mode:         CapacityCheckNone,
+			MinAvailable: 1,
+			Tasks:        []v1alpha1.TaskSpec{newTask("worker", 1, "16", "1Gi")},
+		},
+	}
+
+	for _, testCase := range testCases {
+		CapacityCheck = testCase.Mode
+		job := v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "job",
+				Namespace: "test",
+			},
+			Spec: v1alpha1.JobSpec{
+				MinAvailable: testCase.MinAvailable,
+				Tasks:        testCase.Tasks,
+			},
+		}
+
+		recorder := record.NewFakeRecorder(1)
+		eventRecorder = recorder
+		reviewResponse := v1beta1.AdmissionResponse{Allowed: true}
+		ret := errorsToString(validateClusterCapacity(job, &reviewResponse))
+		if testCase.ExpectErr == "" && ret != "" {
+			t.Errorf("%s: expected no error, but got %s", testCase.Name, ret)
+		}
+		if testCase.ExpectErr != "" && !strings.Contains(ret, testCase.ExpectErr) {
+			t.Errorf("%s: expected error %s, but got %s", testCase.Name, testCase.ExpectErr, ret)
+		}
+		if _, found := reviewResponse.AuditAnnotations[ClusterCapacityWarning]; found != testCase.ExpectWarn {
+			t.Errorf("%s: expected warning as %v but got %v", testCase.Name, testCase.ExpectWarn, found)
+		}
+
+		recordClusterCapacityWarning(&job, &reviewResponse)
+		if recorded := len(recorder.Events) != 0; recorded != testCase.ExpectWarn {
+			t.Errorf("%s: expected warning event as %v but got %v", testCase.Name, testCase.ExpectWarn, recorded)
+		} else if recorded {
+			if event := <-recorder.Events; !strings.HasPrefix(event, "Warning "+ExceedClusterCapacityReason) {
+				t.Errorf("%s: expected warning event of %s, but got %s", testCase.Name, ExceedClusterCapacityReason, event)
+			}
+		}
+	}
+}
+
+func TestParseCapacityCheckMode(t *testing.T) {
+	for _, mode := range []string{"none", "warn", "reject"} {
+		if _, err := ParseCapacityCheckMode(mode); err != nil {
+			t.Errorf("expected mode %s valid, got %v", mode, err)
+		}
+	}
+	if _, err := ParseCapacityCheckMode("deny"); err == nil {
+		t.Errorf("expected mode deny invalid")
+	}
+}
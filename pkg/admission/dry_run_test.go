@@ -0,0 +1,212 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"testing"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	kubebatchclient "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned/fake"
+
+	"k8s.io/api/admission/v1beta1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	busv1alpha1 "volcano.sh/volcano/pkg/apis/bus/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
+	volcanoclient "volcano.sh/volcano/pkg/client/clientset/versioned/fake"
+)
+
+// TestAdmitDryRun checks that the admit functions send no request changing the state of cluster,
+// so they can be registered with sideEffects None and called for dry-run requests.
+func TestAdmitDryRun(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job",
+			Namespace: "test",
+			UID:       "job-uid",
+		},
+		Spec: v1alpha1.JobSpec{
+			MinAvailable: 1,
+			Queue:        "default",
+			Tasks: []v1alpha1.TaskSpec{
+				{
+					Name:     "task",
+					Replicas: 1,
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  "fake-name",
+									Image: "busybox:1.24",
+									Resources: v1.ResourceRequirements{
+										Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	queue := &kbv1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec:       kbv1.QueueSpec{Weight: 1},
+	}
+	podGroup := &kbv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "test"},
+		Spec:       kbv1.PodGroupSpec{MinMember: 1, Queue: "default"},
+	}
+	command := &busv1alpha1.Command{
+		ObjectMeta: metav1.ObjectMeta{Name: "command", Namespace: "test"},
+		Action:     string(v1alpha1.AbortJobAction),
+		TargetObject: &metav1.OwnerReference{
+			APIVersion: helpers.JobKind.GroupVersion().String(),
+			Kind:       helpers.JobKind.Kind,
+			Name:       job.Name,
+			UID:        job.UID,
+		},
+	}
+
+	kubeBatchClient := kubebatchclient.NewSimpleClientset()
+	if _, err := kubeBatchClient.SchedulingV1alpha1().Queues().Create(queue); err != nil {
+		t.Fatalf("Queue Creation Failed: %v", err)
+	}
+	kubeBatchClient.PrependReactor("list", "podgroups", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, &kbv1.PodGroupList{}, nil
+	})
+	volcanoClient := volcanoclient.NewSimpleClientset()
+	if _, err := volcanoClient.BatchV1alpha1().Jobs(job.Namespace).Create(job); err != nil {
+		t.Fatalf("Job Creation Failed: %v", err)
+	}
+	kubeClient := kubeclient.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+	KubeBatchClientSet, VolcanoClientSet, KubeClientSet = kubeBatchClient, volcanoClient, kubeClient
+	podGroupIndexer = newTestPodGroupIndexer()
+	// The job does not fit into the cluster without nodes, which is warned by an event if not dry run.
+	nodeLister = newTestNodeLister()
+	recorder := record.NewFakeRecorder(1)
+	eventRecorder = recorder
+
+	CapacityCheck = CapacityCheckWarn
+	defer func() {
+		CapacityCheck = CapacityCheckNone
+		eventRecorder = nil
+	}()
+	kubeBatchClient.ClearActions()
+	volcanoClient.ClearActions()
+	kubeClient.ClearActions()
+
+	rawJob, _ := json.Marshal(job)
+	rawQueue, _ := json.Marshal(queue)
+	rawPodGroup, _ := json.Marshal(podGroup)
+	rawCommand, _ := json.Marshal(command)
+	jobResource := metav1.GroupVersionResource{
+		Group:    v1alpha1.SchemeGroupVersion.Group,
+		Version:  v1alpha1.SchemeGroupVersion.Version,
+		Resource: "jobs",
+	}
+	podGroupResource := metav1.GroupVersionResource{
+		Group:    kbv1.SchemeGroupVersion.Group,
+		Version:  kbv1.SchemeGroupVersion.Version,
+		Resource: "podgroups",
+	}
+	commandResource := metav1.GroupVersionResource{
+		Group:    busv1alpha1.SchemeGroupVersion.Group,
+		Version:  busv1alpha1.SchemeGroupVersion.Version,
+		Resource: "commands",
+	}
+
+	testCases := []struct {
+		Name      string
+		Admit     AdmitFunc
+		Operation v1beta1.Operation
+		Resource  metav1.GroupVersionResource
+		Raw       []byte
+	}{
+		{Name: "admit job", Admit: AdmitJobs, Operation: v1beta1.Create, Resource: jobResource, Raw: rawJob},
+		{Name: "mutate job", Admit: MutateJobs, Operation: v1beta1.Create, Resource: jobResource, Raw: rawJob},
+		{Name: "admit queue", Admit: AdmitQueues, Operation: v1beta1.Create, Resource: queueResource, Raw: rawQueue},
+		{Name: "delete queue", Admit: AdmitQueues, Operation: v1beta1.Delete, Resource: queueResource},
+		{Name: "mutate queue", Admit: MutateQueues, Operation: v1beta1.Create, Resource: queueResource, Raw: rawQueue},
+		{Name: "admit podgroup", Admit: AdmitPodGroups, Operation: v1beta1.Create, Resource: podGroupResource, Raw: rawPodGroup},
+		{Name: "mutate podgroup", Admit: MutatePodGroups, Operation: v1beta1.Create, Resource: podGroupResource, Raw: rawPodGroup},
+		{Name: "admit command", Admit: AdmitCommands, Operation: v1beta1.Create, Resource: commandResource, Raw: rawCommand},
+	}
+
+	dryRun := true
+	for _, testCase := range testCases {
+		testCase.Admit(v1beta1.AdmissionReview{
+			Request: &v1beta1.AdmissionRequest{
+				Operation: testCase.Operation,
+				Resource:  testCase.Resource,
+				Name:      "default",
+				Namespace: "test",
+				Object:    runtime.RawExtension{Raw: testCase.Raw},
+				DryRun:    &dryRun,
+			},
+		})
+	}
+
+	if len(recorder.Events) != 0 {
+		t.Errorf("unexpected event %s in dry run", <-recorder.Events)
+	}
+
+	var actions []clienttesting.Action
+	actions = append(actions, kubeBatchClient.Actions()...)
+	actions = append(actions, volcanoClient.Actions()...)
+	actions = append(actions, kubeClient.Actions()...)
+	if len(actions) == 0 {
+		t.Errorf("expected lookups of the cluster")
+	}
+	for _, action := range actions {
+		switch {
+		case action.GetVerb() == "get" || action.GetVerb() == "list":
+		case action.GetVerb() == "create" && action.GetResource().Resource == "subjectaccessreviews":
+			// Access reviews are evaluated by apiserver without being persisted.
+		default:
+			t.Errorf("unexpected %s %s in dry run", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+
+	// The warning event is recorded for the request which is not dry run.
+	AdmitJobs(v1beta1.AdmissionReview{
+		Request: &v1beta1.AdmissionRequest{
+			Operation: v1beta1.Create,
+			Resource:  jobResource,
+			Name:      job.Name,
+			Namespace: job.Namespace,
+			Object:    runtime.RawExtension{Raw: rawJob},
+		},
+	})
+	if len(recorder.Events) == 0 {
+		t.Errorf("expected warning event of job exceeding cluster capacity")
+	}
+}
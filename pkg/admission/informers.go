@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"fmt"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	kbinformerfactory "github.com/kubernetes-sigs/kube-batch/pkg/client/informers/externalversions"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	vkscheme "volcano.sh/volcano/pkg/client/clientset/versioned/scheme"
+)
+
+// podGroupQueueIndex is the index of PodGroups by the name of their queue.
+const podGroupQueueIndex = "queue"
+
+var podGroupIndexers = cache.Indexers{
+	podGroupQueueIndex: func(obj interface{}) ([]string, error) {
+		pg, ok := obj.(*kbv1.PodGroup)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object %T", obj)
+		}
+		return []string{pg.Spec.Queue}, nil
+	},
+}
+
+var (
+	// podGroupIndexer caches the PodGroups of cluster, it is set by StartInformers.
+	podGroupIndexer cache.Indexer
+	// nodeLister caches the nodes of cluster, it is set by StartInformers if the capacity check is enabled.
+	nodeLister corelisters.NodeLister
+	// eventRecorder records the events of admitted objects, it is set by StartInformers.
+	eventRecorder record.EventRecorder
+)
+
+// StartInformers starts the informers used by the admit functions and waits for their caches synced,
+// so admitting objects does not list the cluster, and sets up the event recorder; it must be called
+// after KubeBatchClientSet, KubeClientSet and CapacityCheck are set.
+func StartInformers(stopCh <-chan struct{}) error {
+	factory := kbinformerfactory.NewSharedInformerFactory(KubeBatchClientSet, 0)
+	pgInformer := factory.Scheduling().V1alpha1().PodGroups().Informer()
+	if err := pgInformer.AddIndexers(podGroupIndexers); err != nil {
+		return err
+	}
+	podGroupIndexer = pgInformer.GetIndexer()
+	synced := []cache.InformerSynced{pgInformer.HasSynced}
+
+	kubeFactory := informers.NewSharedInformerFactory(KubeClientSet, 0)
+	if CapacityCheck != CapacityCheckNone {
+		nodeInformer := kubeFactory.Core().V1().Nodes()
+		nodeLister = nodeInformer.Lister()
+		synced = append(synced, nodeInformer.Informer().HasSynced)
+	}
+
+	factory.Start(stopCh)
+	kubeFactory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, synced...) {
+		return fmt.Errorf("failed to wait for caches of informers synced")
+	}
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&corev1client.EventSinkImpl{Interface: KubeClientSet.CoreV1().Events("")})
+	eventRecorder = eventBroadcaster.NewRecorder(vkscheme.Scheme, v1.EventSource{Component: "vk-admission"})
+
+	return nil
+}
+
+// listQueuePodGroups returns the PodGroups of queue which are not being deleted.
+func listQueuePodGroups(queue string) ([]*kbv1.PodGroup, error) {
+	objs, err := podGroupIndexer.ByIndex(podGroupQueueIndex, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	var pgs []*kbv1.PodGroup
+	for _, obj := range objs {
+		pg := obj.(*kbv1.PodGroup)
+		if pg.DeletionTimestamp != nil {
+			continue
+		}
+		pgs = append(pgs, pg)
+	}
+
+	return pgs, nil
+}
+
+// countPendingJobs returns the number of jobs in queue whose PodGroups are not enqueued yet.
+// Unlike queue.Status.Pending, which is updated by the queue controller, it follows the
+// PodGroups as soon as they are created.
+func countPendingJobs(queue string) (int32, error) {
+	pgs, err := listQueuePodGroups(queue)
+	if err != nil {
+		return 0, err
+	}
+
+	var pending int32
+	for _, pg := range pgs {
+		// The phase of PodGroup is empty until it is handled by the scheduler.
+		if pg.Status.Phase == "" || pg.Status.Phase == kbv1.PodGroupPending {
+			pending++
+		}
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"testing"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// newTestPodGroupIndexer returns the indexer of PodGroups used instead of the informer.
+func newTestPodGroupIndexer(pgs ...*kbv1.PodGroup) cache.Indexer {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, podGroupIndexers)
+	for _, pg := range pgs {
+		indexer.Add(pg)
+	}
+	return indexer
+}
+
+// newTestNodeLister returns the lister of nodes used instead of the informer.
+func newTestNodeLister(nodes ...*v1.Node) corelisters.NodeLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range nodes {
+		indexer.Add(node)
+	}
+	return corelisters.NewNodeLister(indexer)
+}
+
+func TestListQueuePodGroups(t *testing.T) {
+	newPodGroup := func(name, queue string, deleting bool) *kbv1.PodGroup {
+		pg := &kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Spec:       kbv1.PodGroupSpec{Queue: queue},
+		}
+		if deleting {
+			pg.DeletionTimestamp = &metav1.Time{}
+		}
+		return pg
+	}
+
+	podGroupIndexer = newTestPodGroupIndexer(
+		newPodGroup("pg1", "q1", false),
+		newPodGroup("pg2", "q1", true),
+		newPodGroup("pg3", "q2", false),
+	)
+
+	pgs, err := listQueuePodGroups("q1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(pgs) != 1 || pgs[0].Name != "pg1" {
+		t.Errorf("expected PodGroup pg1 of queue q1, got %v", pgs)
+	}
+}
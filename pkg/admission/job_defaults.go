@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package admission
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// JobDefaultsKey is the key of job defaults in the data of ConfigMap
+const JobDefaultsKey = "job-defaults.yaml"
+
+// JobDefaults is the cluster-wide defaults of jobs, which are set by the mutating webhook
+// if the fields of job are not specified.
+type JobDefaults struct {
+	// SchedulerName is the default scheduler of jobs.
+	SchedulerName string `json:"schedulerName,omitempty"`
+	// PriorityClassName is the default priority class of jobs.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// NamespacePriorityClassNames overrides PriorityClassName for the jobs in the namespaces.
+	NamespacePriorityClassNames map[string]string `json:"namespacePriorityClassNames,omitempty"`
+	// TTLSecondsAfterFinished is the default ttl of finished jobs.
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// MaxRetry is the default max retry of jobs.
+	MaxRetry *int32 `json:"maxRetry,omitempty"`
+	// Plugins are added to jobs if they are not enabled by jobs, e.g. env and svc.
+	Plugins map[string][]string `json:"plugins,omitempty"`
+	// Requests are the default resource requests of containers which request neither the resources
+	// nor limit them.
+	Requests v1.ResourceList `json:"requests,omitempty"`
+}
+
+var (
+	jobDefaultsLock sync.RWMutex
+	jobDefaults     *JobDefaults
+)
+
+// SetJobDefaults sets the defaults of jobs used by MutateJobs, nil disables the defaults.
+func SetJobDefaults(defaults *JobDefaults) {
+	jobDefaultsLock.Lock()
+	defer jobDefaultsLock.Unlock()
+
+	jobDefaults = defaults
+}
+
+func getJobDefaults() *JobDefaults {
+	jobDefaultsLock.RLock()
+	defer jobDefaultsLock.RUnlock()
+
+	return jobDefaults
+}
+
+// ParseJobDefaults parses the job defaults from the data of ConfigMap.
+func ParseJobDefaults(cm *v1.ConfigMap) (*JobDefaults, error) {
+	data, found := cm.Data[JobDefaultsKey]
+	if !found {
+		return nil, fmt.Errorf("key %s not found in ConfigMap %s/%s", JobDefaultsKey, cm.Namespace, cm.Name)
+	}
+
+	defaults := &JobDefaults{}
+	if err := yaml.UnmarshalStrict([]byte(data), defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse job defaults in ConfigMap %s/%s: %v", cm.Namespace, cm.Name, err)
+	}
+	return defaults, nil
+}
+
+// WatchJobDefaults keeps the job defaults in sync with the ConfigMap until stopCh is closed;
+// the previous defaults are kept if the ConfigMap is invalid, and cleared if it is deleted.
+func WatchJobDefaults(client kubernetes.Interface, namespace, name string, stopCh <-chan struct{}) {
+	update := func(obj interface{}) {
+		cm, ok := obj.(*v1.ConfigMap)
+		if !ok {
+			return
+		}
+		defaults, err := ParseJobDefaults(cm)
+		if err != nil {
+			glog.Errorf("Failed to update job defaults: %v", err)
+			return
+		}
+		glog.V(3).Infof("Job defaults are updated from ConfigMap %s/%s: %+v", namespace, name, defaults)
+		SetJobDefaults(defaults)
+	}
+
+	lw := cache.NewListWatchFromClient(client.CoreV1().RESTClient(), "configmaps", namespace,
+		fields.OneTermEqualSelector("metadata.name", name))
+	_, controller := cache.NewInformer(lw, &v1.ConfigMap{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc: update,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			update(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			glog.V(3).Infof("Job defaults are cleared as ConfigMap %s/%s is deleted", namespace, name)
+			SetJobDefaults(nil)
+		},
+	})
+	controller.Run(stopCh)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package admission
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestParseJobDefaults(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Data      map[string]string
+		ExpectErr bool
+	}{
+		{
+			Name: "valid defaults",
+			Data: map[string]string{JobDefaultsKey: `
+schedulerName: kube-batch
+namespacePriorityClassNames:
+  prod: high-priority
+maxRetry: 5
+plugins:
+  env: []
+requests:
+  cpu: 100m
+`},
+		},
+		{
+			Name:      "key not found",
+			Data:      map[string]string{"defaults": ""},
+			ExpectErr: true,
+		},
+		{
+			Name:      "unknown field",
+			Data:      map[string]string{JobDefaultsKey: "scheduler: kube-batch"},
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		defaults, err := ParseJobDefaults(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "job-defaults", Namespace: "volcano-system"},
+			Data:       testCase.Data,
+		})
+		if testCase.ExpectErr != (err != nil) {
+			t.Errorf("%s: expect error %v, but got %v", testCase.Name, testCase.ExpectErr, err)
+			continue
+		}
+		if err == nil && (defaults.SchedulerName != "kube-batch" || *defaults.MaxRetry != 5 ||
+			defaults.NamespacePriorityClassNames["prod"] != "high-priority" || defaults.Requests.Cpu().String() != "100m") {
+			t.Errorf("%s: unexpected defaults %+v", testCase.Name, defaults)
+		}
+	}
+}
+
+func TestCreatePatchWithJobDefaults(t *testing.T) {
+	var ttl int32 = 600
+	var maxRetry int32 = 5
+
+	defaults := &JobDefaults{
+		SchedulerName:               "kube-batch",
+		PriorityClassName:           "normal",
+		NamespacePriorityClassNames: map[string]string{"prod": "high"},
+		TTLSecondsAfterFinished:     &ttl,
+		MaxRetry:                    &maxRetry,
+		Plugins:                     map[string][]string{"env": {}, "svc": {}},
+		Requests:                    v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+	}
+
+	newJob := func(namespace string, container v1.Container) v1alpha1.Job {
+		return v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: namespace},
+			Spec: v1alpha1.JobSpec{
+				Queue: "default",
+				Tasks: []v1alpha1.TaskSpec{
+					{
+						Name:     "task",
+						Replicas: 1,
+						Template: v1.PodTemplateSpec{
+							Spec: v1.PodSpec{Containers: []v1.Container{container}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	specified := newJob("prod", v1.Container{
+		Name: "specified",
+		Resources: v1.ResourceRequirements{
+			Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+		},
+	})
+	specified.Spec.SchedulerName = "default-scheduler"
+	specified.Spec.PriorityClassName = "low"
+	specified.Spec.TTLSecondsAfterFinished = &maxRetry
+	specified.Spec.MaxRetry = 1
+	specified.Spec.Plugins = map[string][]string{"svc": {}}
+
+	testCases := []struct {
+		Name     string
+		Job      v1alpha1.Job
+		Defaults *JobDefaults
+		Expect   string
+	}{
+		{
+			Name:     "no defaults",
+			Job:      newJob("test", v1.Container{Name: "c"}),
+			Defaults: nil,
+			Expect:   "null",
+		},
+		{
+			Name:     "all defaults",
+			Job:      newJob("test", v1.Container{Name: "c"}),
+			Defaults: defaults,
+			Expect: `[{"op":"add","path":"/spec/schedulerName","value":"kube-batch"},` +
+				`{"op":"add","path":"/spec/priorityClassName","value":"normal"},` +
+				`{"op":"add","path":"/spec/ttlSecondsAfterFinished","value":600},` +
+				`{"op":"add","path":"/spec/maxRetry","value":5},` +
+				`{"op":"add","path":"/spec/plugins","value":{"env":[],"svc":[]}},` +
+				`{"op":"replace","path":"/spec/tasks","value":[{"name":"task","replicas":1,"template":{"metadata":{"creationTimestamp":null},` +
+				`"spec":{"containers":[{"name":"c","resources":{"requests":{"cpu":"100m"}}}]}}}]}]`,
+		},
+		{
+			Name:     "specified fields are kept",
+			Job:      specified,
+			Defaults: defaults,
+			Expect:   `[{"op":"add","path":"/spec/plugins/env","value":[]}]`,
+		},
+		{
+			Name:     "priority class of namespace",
+			Job:      newJob("prod", v1.Container{Name: "c", Resources: v1.ResourceRequirements{Requests: defaults.Requests}}),
+			Defaults: &JobDefaults{PriorityClassName: "normal", NamespacePriorityClassNames: map[string]string{"prod": "high"}},
+			Expect:   `[{"op":"add","path":"/spec/priorityClassName","value":"high"}]`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		patch, err := createPatch(testCase.Job, "", testCase.Defaults)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", testCase.Name, err)
+			continue
+		}
+		if string(patch) != testCase.Expect {
+			t.Errorf("%s: expect patch\n%s\nbut got\n%s", testCase.Name, testCase.Expect, string(patch))
+		}
+	}
+}
@@ -20,9 +20,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/golang/glog"
+	"sort"
 	"strconv"
+	"strings"
 
 	"k8s.io/api/admission/v1beta1"
+	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
@@ -48,13 +51,17 @@ func MutateJobs(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
 		return ToAdmissionResponse(err)
 	}
 
+	if job.Namespace == "" {
+		job.Namespace = ar.Request.Namespace
+	}
+
 	reviewResponse := v1beta1.AdmissionResponse{}
 	reviewResponse.Allowed = true
 
 	var patchBytes []byte
 	switch ar.Request.Operation {
 	case v1beta1.Create:
-		patchBytes, err = createPatch(job)
+		patchBytes, err = createPatch(job, ar.Request.UserInfo.Username, getJobDefaults())
 		break
 	default:
 		err = fmt.Errorf("expect operation to be 'CREATE' ")
@@ -73,19 +80,77 @@ func MutateJobs(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
 	return &reviewResponse
 }
 
-func createPatch(job v1alpha1.Job) ([]byte, error) {
+func createPatch(job v1alpha1.Job, user string, defaults *JobDefaults) ([]byte, error) {
 	var patch []patchOperation
+	pathUser := patchJobUser(job, user)
+	if pathUser != nil {
+		patch = append(patch, *pathUser)
+	}
 	pathQueue := patchDefaultQueue(job)
 	if pathQueue != nil {
 		patch = append(patch, *pathQueue)
 	}
-	pathSpec := mutateSpec(job.Spec.Tasks, "/spec/tasks")
+	var requests v1.ResourceList
+	if defaults != nil {
+		patch = append(patch, patchJobDefaults(job, defaults)...)
+		requests = defaults.Requests
+	}
+	pathSpec := mutateSpec(job.Spec.Tasks, "/spec/tasks", requests)
 	if pathSpec != nil {
 		patch = append(patch, *pathSpec)
 	}
 	return json.Marshal(patch)
 }
 
+// patchJobDefaults sets the cluster-wide defaults to the fields not specified by job.
+func patchJobDefaults(job v1alpha1.Job, defaults *JobDefaults) []patchOperation {
+	var patch []patchOperation
+	if job.Spec.SchedulerName == "" && defaults.SchedulerName != "" {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/schedulerName", Value: defaults.SchedulerName})
+	}
+
+	if job.Spec.PriorityClassName == "" {
+		priorityClassName := defaults.PriorityClassName
+		if name, found := defaults.NamespacePriorityClassNames[job.Namespace]; found {
+			priorityClassName = name
+		}
+		if priorityClassName != "" {
+			patch = append(patch, patchOperation{Op: "add", Path: "/spec/priorityClassName", Value: priorityClassName})
+		}
+	}
+
+	if job.Spec.TTLSecondsAfterFinished == nil && defaults.TTLSecondsAfterFinished != nil {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/ttlSecondsAfterFinished", Value: *defaults.TTLSecondsAfterFinished})
+	}
+
+	if job.Spec.MaxRetry == 0 && defaults.MaxRetry != nil {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/maxRetry", Value: *defaults.MaxRetry})
+	}
+
+	if len(job.Spec.Plugins) == 0 {
+		if len(defaults.Plugins) != 0 {
+			patch = append(patch, patchOperation{Op: "add", Path: "/spec/plugins", Value: defaults.Plugins})
+		}
+	} else {
+		var names []string
+		for name := range defaults.Plugins {
+			if _, found := job.Spec.Plugins[name]; !found {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			patch = append(patch, patchOperation{
+				Op:    "add",
+				Path:  "/spec/plugins/" + escapeJSONPointer(name),
+				Value: defaults.Plugins[name],
+			})
+		}
+	}
+
+	return patch
+}
+
 func patchDefaultQueue(job v1alpha1.Job) *patchOperation {
 	//Add default queue if not specified.
 	if job.Spec.Queue == "" {
@@ -94,7 +159,31 @@ func patchDefaultQueue(job v1alpha1.Job) *patchOperation {
 	return nil
 }
 
-func mutateSpec(tasks []v1alpha1.TaskSpec, basePath string) *patchOperation {
+func patchJobUser(job v1alpha1.Job, user string) *patchOperation {
+	// Always overwrite the user annotation, so it can not be faked by the submitter.
+	if len(user) == 0 {
+		return nil
+	}
+	if len(job.Annotations) == 0 {
+		return &patchOperation{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: map[string]string{v1alpha1.JobUserKey: user},
+		}
+	}
+	return &patchOperation{
+		Op:    "add",
+		Path:  "/metadata/annotations/" + escapeJSONPointer(v1alpha1.JobUserKey),
+		Value: user,
+	}
+}
+
+// escapeJSONPointer escapes the reference token of JSON pointer, see RFC 6901.
+func escapeJSONPointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func mutateSpec(tasks []v1alpha1.TaskSpec, basePath string, requests v1.ResourceList) *patchOperation {
 	patched := false
 	for index := range tasks {
 		// add default task name
@@ -103,6 +192,13 @@ func mutateSpec(tasks []v1alpha1.TaskSpec, basePath string) *patchOperation {
 			patched = true
 			tasks[index].Name = v1alpha1.DefaultTaskSpec + strconv.Itoa(index)
 		}
+
+		// add default resource requests
+		for i := range tasks[index].Template.Spec.Containers {
+			if setDefaultRequests(&tasks[index].Template.Spec.Containers[i], requests) {
+				patched = true
+			}
+		}
 	}
 	if !patched {
 		return nil
@@ -113,3 +209,23 @@ func mutateSpec(tasks []v1alpha1.TaskSpec, basePath string) *patchOperation {
 		Value: tasks,
 	}
 }
+
+// setDefaultRequests sets the default requests of resources which are neither requested nor limited by the container,
+// the request of a limited resource is defaulted to its limit by kubernetes.
+func setDefaultRequests(container *v1.Container, requests v1.ResourceList) bool {
+	patched := false
+	for name, quantity := range requests {
+		if _, found := container.Resources.Requests[name]; found {
+			continue
+		}
+		if _, found := container.Resources.Limits[name]; found {
+			continue
+		}
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = v1.ResourceList{}
+		}
+		container.Resources.Requests[name] = quantity.DeepCopy()
+		patched = true
+	}
+	return patched
+}
@@ -118,7 +118,7 @@ func TestCreatePatchExecution(t *testing.T) {
 		},
 	}
 
-	ret := mutateSpec(testCase.Job.Spec.Tasks, "/spec/tasks")
+	ret := mutateSpec(testCase.Job.Spec.Tasks, "/spec/tasks", nil)
 	if ret.Path != testCase.operation.Path || ret.Op != testCase.operation.Op {
 		t.Errorf("testCase %s's expected patch operation %v, but got %v",
 			testCase.Name, testCase.operation, *ret)
@@ -139,3 +139,73 @@ func TestCreatePatchExecution(t *testing.T) {
 	}
 
 }
+
+func TestPatchJobUser(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Job       v1alpha1.Job
+		User      string
+		operation *patchOperation
+	}{
+		{
+			Name: "patch user without annotations",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "job-without-annotations",
+				},
+			},
+			User: "alice",
+			operation: &patchOperation{
+				Op:   "add",
+				Path: "/metadata/annotations",
+			},
+		},
+		{
+			Name: "overwrite faked user",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job-with-faked-user",
+					Annotations: map[string]string{v1alpha1.JobUserKey: "bob"},
+				},
+			},
+			User: "alice",
+			operation: &patchOperation{
+				Op:    "add",
+				Path:  "/metadata/annotations/volcano.sh~1user",
+				Value: "alice",
+			},
+		},
+		{
+			Name: "no user info",
+			Job: v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "job-without-user",
+				},
+			},
+			User:      "",
+			operation: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		ret := patchJobUser(testCase.Job, testCase.User)
+		if testCase.operation == nil {
+			if ret != nil {
+				t.Errorf("testCase %s's expected no patch operation, but got %v", testCase.Name, *ret)
+			}
+			continue
+		}
+		if ret == nil {
+			t.Errorf("testCase %s's expected patch operation %v, but got nil", testCase.Name, *testCase.operation)
+			continue
+		}
+		if ret.Path != testCase.operation.Path || ret.Op != testCase.operation.Op {
+			t.Errorf("testCase %s's expected patch operation %v, but got %v",
+				testCase.Name, *testCase.operation, *ret)
+		}
+		if testCase.operation.Value != nil && ret.Value != testCase.operation.Value {
+			t.Errorf("testCase %s's expected patch value %v, but got %v",
+				testCase.Name, testCase.operation.Value, ret.Value)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/glog"
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MutatePodGroups mutate podgroups
+func MutatePodGroups(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+	glog.V(3).Infof("mutating podgroups")
+
+	podGroup, err := DecodePodGroup(ar.Request.Object, ar.Request.Resource)
+	if err != nil {
+		return ToAdmissionResponse(err)
+	}
+
+	reviewResponse := v1beta1.AdmissionResponse{}
+	reviewResponse.Allowed = true
+
+	var patchBytes []byte
+	switch ar.Request.Operation {
+	case v1beta1.Create:
+		patchBytes, err = createPodGroupPatch(podGroup)
+		break
+	default:
+		err = fmt.Errorf("expect operation to be 'CREATE' ")
+		return ToAdmissionResponse(err)
+	}
+
+	if err != nil {
+		reviewResponse.Result = &metav1.Status{Message: err.Error()}
+		return &reviewResponse
+	}
+	glog.V(3).Infof("AdmissionResponse: patch=%v\n", string(patchBytes))
+	reviewResponse.Patch = patchBytes
+	pt := v1beta1.PatchTypeJSONPatch
+	reviewResponse.PatchType = &pt
+
+	return &reviewResponse
+}
+
+func createPodGroupPatch(podGroup kbv1.PodGroup) ([]byte, error) {
+	var patch []patchOperation
+	//Add default queue if not specified.
+	if podGroup.Spec.Queue == "" {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/queue", Value: DefaultQueue})
+	}
+	return json.Marshal(patch)
+}
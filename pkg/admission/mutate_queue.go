@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/glog"
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	"k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	//DefaultQueueWeight constant stores the default weight of queue
+	DefaultQueueWeight int32 = 1
+)
+
+// MutateQueues mutate queues
+func MutateQueues(ar v1beta1.AdmissionReview) *v1beta1.AdmissionResponse {
+	glog.V(3).Infof("mutating queues")
+
+	queue, err := DecodeQueue(ar.Request.Object, ar.Request.Resource)
+	if err != nil {
+		return ToAdmissionResponse(err)
+	}
+
+	reviewResponse := v1beta1.AdmissionResponse{}
+	reviewResponse.Allowed = true
+
+	var patchBytes []byte
+	switch ar.Request.Operation {
+	case v1beta1.Create:
+		patchBytes, err = createQueuePatch(queue)
+		break
+	default:
+		err = fmt.Errorf("expect operation to be 'CREATE' ")
+		return ToAdmissionResponse(err)
+	}
+
+	if err != nil {
+		reviewResponse.Result = &metav1.Status{Message: err.Error()}
+		return &reviewResponse
+	}
+	glog.V(3).Infof("AdmissionResponse: patch=%v\n", string(patchBytes))
+	reviewResponse.Patch = patchBytes
+	pt := v1beta1.PatchTypeJSONPatch
+	reviewResponse.PatchType = &pt
+
+	return &reviewResponse
+}
+
+func createQueuePatch(queue kbv1.Queue) ([]byte, error) {
+	var patch []patchOperation
+	//Add default weight if not specified.
+	if queue.Spec.Weight == 0 {
+		patch = append(patch, patchOperation{Op: "add", Path: "/spec/weight", Value: DefaultQueueWeight})
+	}
+	return json.Marshal(patch)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package admission
+
+import (
+	"testing"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCreateQueuePatch(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Queue  kbv1.Queue
+		Expect string
+	}{
+		{
+			Name:   "patch default weight",
+			Queue:  kbv1.Queue{ObjectMeta: metav1.ObjectMeta{Name: "no-weight"}},
+			Expect: `[{"op":"add","path":"/spec/weight","value":1}]`,
+		},
+		{
+			Name: "keep weight",
+			Queue: kbv1.Queue{
+				ObjectMeta: metav1.ObjectMeta{Name: "weight"},
+				Spec:       kbv1.QueueSpec{Weight: 3},
+			},
+			Expect: "null",
+		},
+	}
+
+	for _, testCase := range testCases {
+		patch, err := createQueuePatch(testCase.Queue)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", testCase.Name, err)
+		}
+		if string(patch) != testCase.Expect {
+			t.Errorf("%s: expect patch %s, but got %s", testCase.Name, testCase.Expect, string(patch))
+		}
+	}
+}
+
+func TestCreatePodGroupPatch(t *testing.T) {
+	podGroup := kbv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "pg", Namespace: "test"},
+		Spec:       kbv1.PodGroupSpec{MinMember: 1},
+	}
+
+	patch, err := createPodGroupPatch(podGroup)
+	if err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if expect := `[{"op":"add","path":"/spec/queue","value":"default"}]`; string(patch) != expect {
+		t.Errorf("expect patch %s, but got %s", expect, string(patch))
+	}
+}
@@ -29,4 +29,10 @@ const (
 	JobVersion = "volcano.sh/job-version"
 	// JobTypeKey job type key used in labels
 	JobTypeKey = "volcano.sh/job-type"
+	// JobUserKey job annotation key of the user who submitted the job
+	JobUserKey = "volcano.sh/user"
+	// QueueMaxRunningJobsKey queue annotation key of the maximum number of running jobs
+	QueueMaxRunningJobsKey = "volcano.sh/max-running-jobs"
+	// QueueMaxPendingJobsKey queue annotation key of the maximum number of pending jobs
+	QueueMaxPendingJobsKey = "volcano.sh/max-pending-jobs"
 )
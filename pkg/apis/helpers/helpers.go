@@ -17,16 +17,22 @@ limitations under the License.
 package helpers
 
 import (
+	"sort"
+	"strconv"
+
 	"github.com/golang/glog"
 
 	"k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
 	vkbatchv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	vkcorev1 "volcano.sh/volcano/pkg/apis/bus/v1alpha1"
@@ -130,3 +136,118 @@ func DeleteConfigmap(job *vkv1.Job, kubeClients kubernetes.Interface, cmName str
 
 	return nil
 }
+
+// GetQueueJobLimit returns the job number limit set by the annotation key on queue,
+// and whether a valid limit is set.
+func GetQueueJobLimit(queue *kbv1.Queue, key string) (int32, bool) {
+	value, found := queue.Annotations[key]
+	if !found {
+		return 0, false
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || limit < 0 {
+		glog.Warningf("Invalid value <%s> of annotation <%s> on Queue <%s>, ignore it.",
+			value, key, queue.Name)
+		return 0, false
+	}
+
+	return int32(limit), true
+}
+
+// GetTaskRequests returns the resource requests of one pod of the task. As the init containers run
+// one by one before the containers, the pod requests the larger of the sum of containers and the
+// largest init container for each resource, the same as the scheduler.
+func GetTaskRequests(task *vkv1.TaskSpec) v1.ResourceList {
+	requests := v1.ResourceList{}
+	for _, c := range task.Template.Spec.Containers {
+		addResourceList(requests, c.Resources.Requests, 1)
+	}
+	for _, c := range task.Template.Spec.InitContainers {
+		for name, quantity := range c.Resources.Requests {
+			if value, found := requests[name]; !found || quantity.Cmp(value) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	return requests
+}
+
+// GetJobRequests returns the total resource requests of all pods of the job.
+func GetJobRequests(job *vkv1.Job) v1.ResourceList {
+	requests := v1.ResourceList{}
+	for i := range job.Spec.Tasks {
+		addResourceList(requests, GetTaskRequests(&job.Spec.Tasks[i]), job.Spec.Tasks[i].Replicas)
+	}
+	return requests
+}
+
+// GetJobMinRequests returns the resource requests the job needs at least to be scheduled as a gang,
+// i.e. its minAvailable pods. As the pods to start first depend on task priority, the cheapest
+// minAvailable pods are taken for each resource, which makes the result a lower bound.
+func GetJobMinRequests(job *vkv1.Job) v1.ResourceList {
+	tasks := job.Spec.Tasks
+	taskRequests := make([]v1.ResourceList, len(tasks))
+	names := map[v1.ResourceName]struct{}{}
+	for i := range tasks {
+		taskRequests[i] = GetTaskRequests(&tasks[i])
+		for name := range taskRequests[i] {
+			names[name] = struct{}{}
+		}
+	}
+
+	requests := v1.ResourceList{}
+	for name := range names {
+		// take the pods of the cheapest tasks first, tasks without request of this resource are the cheapest ones
+		order := make([]int, len(tasks))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			left, right := taskRequests[order[i]][name], taskRequests[order[j]][name]
+			return left.Cmp(right) < 0
+		})
+
+		value := resource.Quantity{}
+		remaining := job.Spec.MinAvailable
+		for _, i := range order {
+			if remaining <= 0 {
+				break
+			}
+			count := tasks[i].Replicas
+			if count > remaining {
+				count = remaining
+			}
+			if count <= 0 {
+				continue
+			}
+			value.Add(multiplyQuantity(taskRequests[i][name], count))
+			remaining -= count
+		}
+		if !value.IsZero() {
+			requests[name] = value
+		}
+	}
+	return requests
+}
+
+func addResourceList(list, requests v1.ResourceList, times int32) {
+	for name, quantity := range requests {
+		value := list[name]
+		value.Add(multiplyQuantity(quantity, times))
+		list[name] = value
+	}
+}
+
+// multiplyQuantity returns the quantity multiplied by times with O(log(times)) additions;
+// Quantity.Add does not overflow for large quantities.
+func multiplyQuantity(quantity resource.Quantity, times int32) resource.Quantity {
+	result := resource.Quantity{Format: quantity.Format}
+	for ; times > 0; times >>= 1 {
+		if times&1 == 1 {
+			result.Add(quantity)
+		}
+		quantity.Add(quantity)
+	}
+	return result
+}
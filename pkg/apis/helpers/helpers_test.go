@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestGetJobRequests(t *testing.T) {
+	newTask := func(replicas int32, requests v1.ResourceList) vkv1.TaskSpec {
+		return vkv1.TaskSpec{
+			Replicas: replicas,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{{Resources: v1.ResourceRequirements{Requests: requests}}},
+				},
+			},
+		}
+	}
+	cpu := func(value string) v1.ResourceList {
+		return v1.ResourceList{v1.ResourceCPU: resource.MustParse(value)}
+	}
+
+	testCases := []struct {
+		Name          string
+		MinAvailable  int32
+		Tasks         []vkv1.TaskSpec
+		ExpectMinCPU  string
+		ExpectJobCPU  string
+		ExpectMinMiss bool
+	}{
+		{
+			Name:         "cheapest tasks first",
+			MinAvailable: 3,
+			Tasks:        []vkv1.TaskSpec{newTask(2, cpu("4")), newTask(2, cpu("1"))},
+			ExpectMinCPU: "6",
+			ExpectJobCPU: "10",
+		},
+		{
+			Name:          "tasks without requests are the cheapest",
+			MinAvailable:  2,
+			Tasks:         []vkv1.TaskSpec{newTask(1, cpu("4")), newTask(2, nil)},
+			ExpectJobCPU:  "4",
+			ExpectMinMiss: true,
+		},
+		{
+			Name:         "large replicas",
+			MinAvailable: 1000000000,
+			Tasks:        []vkv1.TaskSpec{newTask(2000000000, cpu("500m"))},
+			ExpectMinCPU: "500M",
+			ExpectJobCPU: "1G",
+		},
+	}
+
+	for _, testCase := range testCases {
+		job := &vkv1.Job{Spec: vkv1.JobSpec{MinAvailable: testCase.MinAvailable, Tasks: testCase.Tasks}}
+
+		minCPU, found := GetJobMinRequests(job)[v1.ResourceCPU]
+		if testCase.ExpectMinMiss {
+			if found {
+				t.Errorf("%s: expected no min cpu, got %s", testCase.Name, minCPU.String())
+			}
+		} else if minCPU.Cmp(resource.MustParse(testCase.ExpectMinCPU)) != 0 {
+			t.Errorf("%s: expected min cpu %s, got %s", testCase.Name, testCase.ExpectMinCPU, minCPU.String())
+		}
+
+		jobCPU := GetJobRequests(job)[v1.ResourceCPU]
+		if jobCPU.Cmp(resource.MustParse(testCase.ExpectJobCPU)) != 0 {
+			t.Errorf("%s: expected job cpu %s, got %s", testCase.Name, testCase.ExpectJobCPU, jobCPU.String())
+		}
+	}
+}
+
+func TestGetTaskRequests(t *testing.T) {
+	newContainer := func(cpu, memory string) v1.Container {
+		return v1.Container{
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceCPU:    resource.MustParse(cpu),
+					v1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+
+	task := &vkv1.TaskSpec{
+		Template: v1.PodTemplateSpec{
+			Spec: v1.PodSpec{
+				InitContainers: []v1.Container{
+					newContainer("4", "1Gi"),
+					newContainer("1", "2Gi"),
+					{
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+						},
+					},
+				},
+				Containers: []v1.Container{newContainer("1", "2Gi"), newContainer("1", "2Gi")},
+			},
+		},
+	}
+
+	requests := GetTaskRequests(task)
+	for name, expect := range map[v1.ResourceName]string{
+		v1.ResourceCPU:    "4",
+		v1.ResourceMemory: "4Gi",
+		"nvidia.com/gpu":  "1",
+	} {
+		if value := requests[name]; value.Cmp(resource.MustParse(expect)) != 0 {
+			t.Errorf("expected %s requests %s, got %s", name, expect, value.String())
+		}
+	}
+}
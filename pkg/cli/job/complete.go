@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+type completeFlags struct {
+	commonFlags
+
+	Namespace string
+	JobName   string
+	Reason    string
+	Message   string
+}
+
+var completeJobFlags = &completeFlags{}
+
+// InitCompleteFlags init complete related flags
+func InitCompleteFlags(cmd *cobra.Command) {
+	initFlags(cmd, &completeJobFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&completeJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
+	cmd.Flags().StringVarP(&completeJobFlags.JobName, "name", "n", "", "the name of job")
+	cmd.Flags().StringVarP(&completeJobFlags.Reason, "reason", "", "", "the one-word, CamelCase reason of completing job")
+	cmd.Flags().StringVarP(&completeJobFlags.Message, "message", "", "", "the human-readable message of completing job")
+}
+
+// CompleteJob completes the job
+func CompleteJob() error {
+	config, err := buildConfig(&completeJobFlags.commonFlags, &completeJobFlags.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if completeJobFlags.JobName == "" {
+		err := fmt.Errorf("job name is mandatory to complete a particular job")
+		return err
+	}
+
+	return createJobCommand(config,
+		completeJobFlags.Namespace, completeJobFlags.JobName,
+		v1alpha1.CompleteJobAction, completeJobFlags.Reason, completeJobFlags.Message)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1alpha1batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	v1alpha1 "volcano.sh/volcano/pkg/apis/bus/v1alpha1"
+)
+
+func TestCompleteJob(t *testing.T) {
+	responsejob := v1alpha1batch.Job{}
+
+	var command *v1alpha1.Command
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var response interface{}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "commands"):
+			command = &v1alpha1.Command{}
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, command)
+			response = command
+		default:
+			response = responsejob
+		}
+		val, err := json.Marshal(response)
+		if err == nil {
+			w.Write(val)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	completeJobFlags.Master = server.URL
+	completeJobFlags.Namespace = "test"
+	completeJobFlags.JobName = "testjob"
+
+	testCases := []struct {
+		Name    string
+		Reason  string
+		Message string
+	}{
+		{
+			Name: "CompleteJob",
+		},
+		{
+			Name:    "CompleteJobWithReason",
+			Reason:  "Finished",
+			Message: "results are collected",
+		},
+	}
+
+	for i, testcase := range testCases {
+		command = nil
+		completeJobFlags.Reason = testcase.Reason
+		completeJobFlags.Message = testcase.Message
+
+		err := CompleteJob()
+		if err != nil {
+			t.Errorf("case %d (%s): expected no error, got %v ", i, testcase.Name, err)
+		}
+		if command == nil || command.Action != string(v1alpha1batch.CompleteJobAction) ||
+			command.Reason != testcase.Reason || command.Message != testcase.Message {
+			t.Errorf("case %d (%s): expected command %s with reason %q and message %q, got %v",
+				i, testcase.Name, v1alpha1batch.CompleteJobAction, testcase.Reason, testcase.Message, command)
+		}
+	}
+	completeJobFlags.Reason = ""
+	completeJobFlags.Message = ""
+}
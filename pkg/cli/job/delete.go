@@ -39,13 +39,13 @@ var deleteJobFlags = &deleteFlags{}
 func InitDeleteFlags(cmd *cobra.Command) {
 	initFlags(cmd, &deleteJobFlags.commonFlags)
 
-	cmd.Flags().StringVarP(&deleteJobFlags.Namespace, "namespace", "N", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&deleteJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
 	cmd.Flags().StringVarP(&deleteJobFlags.JobName, "name", "n", "", "the name of job")
 }
 
 // DeleteJob  delete the job
 func DeleteJob() error {
-	config, err := buildConfig(deleteJobFlags.Master, deleteJobFlags.Kubeconfig)
+	config, err := buildConfig(&deleteJobFlags.commonFlags, &deleteJobFlags.Namespace)
 	if err != nil {
 		return err
 	}
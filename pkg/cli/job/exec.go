@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/docker/docker/pkg/term"
+	"github.com/spf13/cobra"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+type execFlags struct {
+	commonFlags
+
+	Namespace string
+	JobName   string
+	TaskName  string
+	Index     int
+	Container string
+	Stdin     bool
+	TTY       bool
+}
+
+var execJobFlags = &execFlags{}
+
+// InitExecFlags init the exec command flags
+func InitExecFlags(cmd *cobra.Command) {
+	initFlags(cmd, &execJobFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&execJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
+	cmd.Flags().StringVarP(&execJobFlags.JobName, "name", "n", "", "the name of job, instead of the first argument")
+	cmd.Flags().StringVarP(&execJobFlags.TaskName, "task", "t", "", "the name of task")
+	cmd.Flags().IntVarP(&execJobFlags.Index, "index", "i", 0, "the index of pod in task")
+	cmd.Flags().StringVarP(&execJobFlags.Container, "container", "c", "", "the container name, the first container of pod is used if not set")
+	cmd.Flags().BoolVarP(&execJobFlags.Stdin, "stdin", "", false, "pass stdin to the container")
+	cmd.Flags().BoolVarP(&execJobFlags.TTY, "tty", "", false, "stdin is a TTY")
+}
+
+// ExecJob executes the command in the pod of the task, the args before "--" are the job which can also be
+// specified by --name, the args after "--" are the command
+func ExecJob(args []string, argsLenAtDash int) error {
+	config, err := buildConfig(&execJobFlags.commonFlags, &execJobFlags.Namespace)
+	if err != nil {
+		return err
+	}
+
+	var command []string
+	if argsLenAtDash >= 0 {
+		args, command = args[:argsLenAtDash], args[argsLenAtDash:]
+	}
+	jobName, args := jobNameFromArgs(execJobFlags.JobName, args)
+	if jobName == "" {
+		err := fmt.Errorf("job name (specified by the first argument or --name) is mandatory to exec in a particular job")
+		return err
+	}
+	if len(args) != 0 {
+		err := fmt.Errorf("unexpected arguments %v, the command is passed after --, e.g. vkctl job exec job -t task -i 0 -- sh", args)
+		return err
+	}
+	if len(command) == 0 {
+		err := fmt.Errorf("command is mandatory, e.g. vkctl job exec job -t task -i 0 -- sh")
+		return err
+	}
+
+	pod, err := getTaskPod(config, execJobFlags.Namespace, jobName, execJobFlags.TaskName, execJobFlags.Index)
+	if err != nil {
+		return err
+	}
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return fmt.Errorf("cannot exec into pod %s/%s, it is %s", pod.Namespace, pod.Name, pod.Status.Phase)
+	}
+
+	container := execJobFlags.Container
+	if container == "" && len(pod.Spec.Containers) != 0 {
+		container = pod.Spec.Containers[0].Name
+	}
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	req := kubeClient.CoreV1().RESTClient().Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     execJobFlags.Stdin,
+			Stdout:    true,
+			// The stderr is merged into stdout by the TTY.
+			Stderr: !execJobFlags.TTY,
+			TTY:    execJobFlags.TTY,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	options := remotecommand.StreamOptions{
+		Stdout: os.Stdout,
+		Tty:    execJobFlags.TTY,
+	}
+	if execJobFlags.Stdin {
+		options.Stdin = os.Stdin
+	}
+	if !execJobFlags.TTY {
+		options.Stderr = os.Stderr
+	}
+
+	// The local terminal is set to raw mode, so the input is passed to the TTY of container as is.
+	if execJobFlags.TTY && execJobFlags.Stdin {
+		if fd, isTerminal := term.GetFdInfo(os.Stdin); isTerminal {
+			state, err := term.SetRawTerminal(fd)
+			if err != nil {
+				return err
+			}
+			defer term.RestoreTerminal(fd, state)
+
+			options.TerminalSizeQueue = newTerminalSizeQueue(fd)
+		}
+	}
+
+	return executor.Stream(options)
+}
+
+// terminalSizeQueue returns the size of local terminal once, the later resizing is not passed to the container
+type terminalSizeQueue struct {
+	sizes chan remotecommand.TerminalSize
+}
+
+func newTerminalSizeQueue(fd uintptr) remotecommand.TerminalSizeQueue {
+	queue := &terminalSizeQueue{sizes: make(chan remotecommand.TerminalSize, 1)}
+	if size, err := term.GetWinsize(fd); err == nil {
+		queue.sizes <- remotecommand.TerminalSize{Width: size.Width, Height: size.Height}
+	}
+	close(queue.sizes)
+	return queue
+}
+
+// Next returns the size of terminal, or nil if it has been returned
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q.sizes
+	if !ok {
+		return nil
+	}
+	return &size
+}
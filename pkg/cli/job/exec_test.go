@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newStreamingServer returns the server which serves the pods of task, and records the streaming request
+// of pod instead of upgrading it.
+func newStreamingServer(phase v1.PodPhase, streamRequest **http.Request) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			*streamRequest = r
+			http.Error(w, "upgrade is not supported", http.StatusBadRequest)
+			return
+		}
+
+		response := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:],
+				Namespace: "test",
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "main"}, {Name: "sidecar"}},
+			},
+			Status: v1.PodStatus{Phase: phase},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		val, err := json.Marshal(response)
+		if err == nil {
+			w.Write(val)
+		}
+	}))
+}
+
+func TestExecJob(t *testing.T) {
+	var streamRequest *http.Request
+	server := newStreamingServer(v1.PodRunning, &streamRequest)
+	defer server.Close()
+
+	execJobFlags.Master = server.URL
+	execJobFlags.Namespace = "test"
+	execJobFlags.Index = 3
+	execJobFlags.AsUser = "alice"
+	execJobFlags.AsGroups = []string{"dev", "ops"}
+
+	testCases := []struct {
+		Name          string
+		JobName       string
+		TaskName      string
+		Container     string
+		Args          []string
+		ArgsLenAtDash int
+		ExpectQuery   string
+	}{
+		{
+			Name:          "ExecJob",
+			TaskName:      "worker",
+			Container:     "sidecar",
+			Args:          []string{"testjob", "ls", "-l"},
+			ArgsLenAtDash: 1,
+			ExpectQuery:   "command=ls&command=-l&container=sidecar&stderr=true&stdout=true",
+		},
+		{
+			Name:          "JobNameFlag",
+			JobName:       "testjob",
+			TaskName:      "worker",
+			Args:          []string{"ls", "testjob"},
+			ArgsLenAtDash: 0,
+			ExpectQuery:   "command=ls&command=testjob&container=main&stderr=true&stdout=true",
+		},
+		{
+			Name:          "DefaultContainer",
+			TaskName:      "worker",
+			Args:          []string{"testjob", "ls"},
+			ArgsLenAtDash: 1,
+			ExpectQuery:   "command=ls&container=main&stderr=true&stdout=true",
+		},
+		{
+			Name:          "NoJob",
+			TaskName:      "worker",
+			Args:          []string{"ls"},
+			ArgsLenAtDash: 0,
+		},
+		{
+			Name:          "NoDash",
+			TaskName:      "worker",
+			Args:          []string{"testjob", "ls"},
+			ArgsLenAtDash: -1,
+		},
+		{
+			Name:          "NoTask",
+			TaskName:      "",
+			Args:          []string{"testjob", "ls"},
+			ArgsLenAtDash: 1,
+		},
+		{
+			Name:          "NoCommand",
+			TaskName:      "worker",
+			Args:          []string{"testjob"},
+			ArgsLenAtDash: 1,
+		},
+	}
+
+	for i, testcase := range testCases {
+		streamRequest = nil
+		execJobFlags.JobName = testcase.JobName
+		execJobFlags.TaskName = testcase.TaskName
+		execJobFlags.Container = testcase.Container
+
+		// The command is not executed since the test server does not upgrade the connection.
+		if err := ExecJob(testcase.Args, testcase.ArgsLenAtDash); err == nil {
+			t.Errorf("case %d (%s): expected error, got nil", i, testcase.Name)
+		}
+		if testcase.ExpectQuery == "" {
+			if streamRequest != nil {
+				t.Errorf("case %d (%s): expected no exec request, got %s", i, testcase.Name, streamRequest.URL)
+			}
+			continue
+		}
+
+		if streamRequest == nil {
+			t.Errorf("case %d (%s): expected exec request, got nil", i, testcase.Name)
+			continue
+		}
+		if streamRequest.URL.Path != "/api/v1/namespaces/test/pods/testjob-worker-3/exec" ||
+			streamRequest.URL.RawQuery != testcase.ExpectQuery {
+			t.Errorf("case %d (%s): expected exec request of pod testjob-worker-3 with %s, got %s",
+				i, testcase.Name, testcase.ExpectQuery, streamRequest.URL)
+		}
+		if upgrade := streamRequest.Header.Get("Upgrade"); upgrade != "SPDY/3.1" {
+			t.Errorf("case %d (%s): expected exec request upgraded to SPDY/3.1, got %q", i, testcase.Name, upgrade)
+		}
+		// The streaming request is sent with the connection flags, like other requests.
+		if user, groups := streamRequest.Header.Get("Impersonate-User"), streamRequest.Header["Impersonate-Group"]; user != "alice" ||
+			strings.Join(groups, ",") != "dev,ops" {
+			t.Errorf("case %d (%s): expected exec request as alice in groups dev,ops, got %q in %v",
+				i, testcase.Name, user, groups)
+		}
+	}
+	execJobFlags.JobName = ""
+	execJobFlags.Container = ""
+	execJobFlags.AsUser = ""
+	execJobFlags.AsGroups = nil
+}
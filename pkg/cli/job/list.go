@@ -20,12 +20,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/cli/util"
 	"volcano.sh/volcano/pkg/client/clientset/versioned"
 )
 
@@ -34,6 +39,15 @@ type listFlags struct {
 
 	Namespace     string
 	SchedulerName string
+	Watch         bool
+	Output        string
+
+	AllNamespaces bool
+	Selector      string
+	Phases        []string
+	Queue         string
+	Since         time.Duration
+	SortBy        string
 }
 
 const (
@@ -66,6 +80,26 @@ const (
 	RetryCount string = "RetryCount"
 	// JobType  job type
 	JobType string = "JobType"
+	// Queue queue
+	Queue string = "Queue"
+	// Namespace namespace
+	Namespace string = "Namespace"
+	// Node node
+	Node string = "Node"
+	// Restarts restarts
+	Restarts string = "Restarts"
+
+	// JobResource is the resource name of job, used in the name output
+	JobResource string = "job." + v1alpha1.GroupName
+)
+
+const (
+	// SortByCreation sorts jobs by creation time, the newest first
+	SortByCreation = "creation"
+	// SortByDuration sorts jobs by running duration, the longest first
+	SortByDuration = "duration"
+	// SortByRetries sorts jobs by retry count, the most first
+	SortByRetries = "retries"
 )
 
 var listJobFlags = &listFlags{}
@@ -74,23 +108,68 @@ var listJobFlags = &listFlags{}
 func InitListFlags(cmd *cobra.Command) {
 	initFlags(cmd, &listJobFlags.commonFlags)
 
-	cmd.Flags().StringVarP(&listJobFlags.Namespace, "namespace", "N", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&listJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
 	cmd.Flags().StringVarP(&listJobFlags.SchedulerName, "scheduler", "S", "", "list job with specified scheduler name")
+	cmd.Flags().BoolVarP(&listJobFlags.Watch, "watch", "w", false, "watch the jobs and redraw them on change")
+	cmd.Flags().StringVarP(&listJobFlags.Output, "output", "o", "", util.OutputUsage)
+	cmd.Flags().BoolVarP(&listJobFlags.AllNamespaces, "all-namespaces", "A", false, "list jobs across all namespaces")
+	cmd.Flags().StringVarP(&listJobFlags.Selector, "selector", "l", "", "label selector to filter jobs, e.g. -l key1=value1,key2=value2")
+	cmd.Flags().StringSliceVarP(&listJobFlags.Phases, "phase", "p", nil, "list job in the specified phases, e.g. --phase Failed,Aborted")
+	cmd.Flags().StringVarP(&listJobFlags.Queue, "queue", "q", "", "list job in the specified queue")
+	cmd.Flags().DurationVarP(&listJobFlags.Since, "since", "", 0, "list job created within the duration, e.g. --since 2h")
+	cmd.Flags().StringVarP(&listJobFlags.SortBy, "sort-by", "", "",
+		fmt.Sprintf("sort jobs by one of: %s|%s|%s", SortByCreation, SortByDuration, SortByRetries))
 }
 
 // ListJobs  lists all jobs details
 func ListJobs() error {
-	config, err := buildConfig(listJobFlags.Master, listJobFlags.Kubeconfig)
+	config, err := buildConfig(&listJobFlags.commonFlags, &listJobFlags.Namespace)
 	if err != nil {
 		return err
 	}
 
+	if err := util.ValidateOutput(listJobFlags.Output); err != nil {
+		return err
+	}
+	if listJobFlags.Since < 0 {
+		return fmt.Errorf("duration of --since can not be negative")
+	}
+
+	switch listJobFlags.SortBy {
+	case "", SortByCreation, SortByDuration, SortByRetries:
+	default:
+		return fmt.Errorf("unsupported sort key %q, expected one of: %s|%s|%s",
+			listJobFlags.SortBy, SortByCreation, SortByDuration, SortByRetries)
+	}
+
+	namespace := listJobFlags.Namespace
+	if listJobFlags.AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+
+	if listJobFlags.Watch {
+		// The jobs are redrawn as tables on change, other output formats are not streamed.
+		if !util.IsTableOutput(listJobFlags.Output) {
+			return fmt.Errorf("output format %s is not supported with --watch", listJobFlags.Output)
+		}
+		return watchJobs(config, namespace, "", listJobFlags.Selector, os.Stdout, wait.NeverStop)
+	}
+
 	jobClient := versioned.NewForConfigOrDie(config)
-	jobs, err := jobClient.BatchV1alpha1().Jobs(listJobFlags.Namespace).List(metav1.ListOptions{})
+	jobs, err := jobClient.BatchV1alpha1().Jobs(namespace).List(metav1.ListOptions{
+		LabelSelector: listJobFlags.Selector,
+	})
 	if err != nil {
 		return err
 	}
 
+	now := time.Now()
+	jobs = filterJobs(jobs, now)
+	sortJobs(jobs, listJobFlags.SortBy, now)
+	if !util.IsTableOutput(listJobFlags.Output) {
+		return util.PrintObject(listJobFlags.Output, JobResource, jobs, os.Stdout)
+	}
+
 	if len(jobs.Items) == 0 {
 		fmt.Printf("No resources found\n")
 		return nil
@@ -102,17 +181,24 @@ func ListJobs() error {
 
 // PrintJobs prints all jobs details
 func PrintJobs(jobs *v1alpha1.JobList, writer io.Writer) {
+	wide := listJobFlags.Output == util.OutputWide
 	maxNameLen := getMaxNameLen(jobs)
-	_, err := fmt.Fprintf(writer, fmt.Sprintf("%%-%ds%%-25s%%-12s%%-12s%%-12s%%-6s%%-10s%%-10s%%-12s%%-10s%%-12s\n", maxNameLen),
+	maxNamespaceLen := getMaxNamespaceLen(jobs)
+	header := ""
+	if listJobFlags.AllNamespaces {
+		header = fmt.Sprintf("%-*s", maxNamespaceLen, Namespace)
+	}
+	header += fmt.Sprintf(fmt.Sprintf("%%-%ds%%-25s%%-12s%%-12s%%-12s%%-6s%%-10s%%-10s%%-12s%%-10s%%-12s", maxNameLen),
 		Name, Creation, Phase, JobType, Replicas, Min, Pending, Running, Succeeded, Failed, RetryCount)
+	if wide {
+		header += fmt.Sprintf("%-15s%-12s%s", Queue, Version, Scheduler)
+	}
+	_, err := fmt.Fprintln(writer, header)
 	if err != nil {
 		fmt.Printf("Failed to print list command result: %s.\n", err)
 	}
 
 	for _, job := range jobs.Items {
-		if listJobFlags.SchedulerName != "" && listJobFlags.SchedulerName != job.Spec.SchedulerName {
-			continue
-		}
 		replicas := int32(0)
 		for _, ts := range job.Spec.Tasks {
 			replicas += ts.Replicas
@@ -121,15 +207,88 @@ func PrintJobs(jobs *v1alpha1.JobList, writer io.Writer) {
 		if jobType == "" {
 			jobType = "Batch"
 		}
-		_, err = fmt.Fprintf(writer, fmt.Sprintf("%%-%ds%%-25s%%-12s%%-12s%%-12d%%-6d%%-10d%%-10d%%-12d%%-10d%%-12d\n", maxNameLen),
+		row := ""
+		if listJobFlags.AllNamespaces {
+			row = fmt.Sprintf("%-*s", maxNamespaceLen, job.Namespace)
+		}
+		row += fmt.Sprintf(fmt.Sprintf("%%-%ds%%-25s%%-12s%%-12s%%-12d%%-6d%%-10d%%-10d%%-12d%%-10d%%-12d", maxNameLen),
 			job.Name, job.CreationTimestamp.Format("2006-01-02 15:04:05"), job.Status.State.Phase, jobType, replicas,
 			job.Status.MinAvailable, job.Status.Pending, job.Status.Running, job.Status.Succeeded, job.Status.Failed, job.Status.RetryCount)
+		if wide {
+			row += fmt.Sprintf("%-15s%-12d%s", job.Spec.Queue, job.Status.Version, job.Spec.SchedulerName)
+		}
+		_, err = fmt.Fprintln(writer, row)
 		if err != nil {
 			fmt.Printf("Failed to print list command result: %s.\n", err)
 		}
 	}
 }
 
+// filterJobs returns the jobs matching the filter flags
+func filterJobs(jobs *v1alpha1.JobList, now time.Time) *v1alpha1.JobList {
+	result := &v1alpha1.JobList{ListMeta: jobs.ListMeta}
+	for _, job := range jobs.Items {
+		if listJobFlags.SchedulerName != "" && listJobFlags.SchedulerName != job.Spec.SchedulerName {
+			continue
+		}
+		if listJobFlags.Queue != "" && listJobFlags.Queue != job.Spec.Queue {
+			continue
+		}
+		if len(listJobFlags.Phases) != 0 && !containsPhase(listJobFlags.Phases, job.Status.State.Phase) {
+			continue
+		}
+		if listJobFlags.Since > 0 && job.CreationTimestamp.Time.Before(now.Add(-listJobFlags.Since)) {
+			continue
+		}
+		result.Items = append(result.Items, job)
+	}
+	return result
+}
+
+func containsPhase(phases []string, phase v1alpha1.JobPhase) bool {
+	for _, p := range phases {
+		if strings.EqualFold(p, string(phase)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortJobs sorts the jobs by the key, the jobs are kept in order of namespace and name if key is empty
+func sortJobs(jobs *v1alpha1.JobList, sortBy string, now time.Time) {
+	items := jobs.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		switch sortBy {
+		case SortByCreation:
+			if !items[i].CreationTimestamp.Equal(&items[j].CreationTimestamp) {
+				return items[j].CreationTimestamp.Before(&items[i].CreationTimestamp)
+			}
+		case SortByDuration:
+			if di, dj := jobDuration(&items[i], now), jobDuration(&items[j], now); di != dj {
+				return di > dj
+			}
+		case SortByRetries:
+			if items[i].Status.RetryCount != items[j].Status.RetryCount {
+				return items[i].Status.RetryCount > items[j].Status.RetryCount
+			}
+		}
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+}
+
+// jobDuration returns how long the job has run, until it finished or now
+func jobDuration(job *v1alpha1.Job, now time.Time) time.Duration {
+	switch job.Status.State.Phase {
+	case v1alpha1.Completed, v1alpha1.Failed, v1alpha1.Terminated, v1alpha1.Aborted:
+		return job.Status.State.LastTransitionTime.Sub(job.CreationTimestamp.Time)
+	default:
+		return now.Sub(job.CreationTimestamp.Time)
+	}
+}
+
 func getMaxNameLen(jobs *v1alpha1.JobList) int {
 	maxLen := len(Name)
 	for _, job := range jobs.Items {
@@ -140,3 +299,14 @@ func getMaxNameLen(jobs *v1alpha1.JobList) int {
 
 	return maxLen + 3
 }
+
+func getMaxNamespaceLen(jobs *v1alpha1.JobList) int {
+	maxLen := len(Namespace)
+	for _, job := range jobs.Items {
+		if len(job.Namespace) > maxLen {
+			maxLen = len(job.Namespace)
+		}
+	}
+
+	return maxLen + 3
+}
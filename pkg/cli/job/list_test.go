@@ -20,7 +20,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 )
@@ -61,4 +65,99 @@ func TestListJob(t *testing.T) {
 		}
 	}
 
+	// The jobs are only watched as tables.
+	listJobFlags.Watch = true
+	listJobFlags.Output = "json"
+	if err := ListJobs(); err == nil {
+		t.Errorf("expected error of watching jobs with output json, got nil")
+	}
+	listJobFlags.Watch = false
+	listJobFlags.Output = ""
+}
+
+func TestFilterAndSortJobs(t *testing.T) {
+	now := time.Now()
+	newJob := func(namespace, name, queue string, phase v1alpha1.JobPhase, age time.Duration, retries int32) v1alpha1.Job {
+		job := v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         namespace,
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Spec: v1alpha1.JobSpec{Queue: queue},
+		}
+		job.Status.State.Phase = phase
+		job.Status.State.LastTransitionTime = metav1.NewTime(now.Add(-age / 2))
+		job.Status.RetryCount = retries
+		return job
+	}
+
+	jobs := &v1alpha1.JobList{}
+	jobs.Items = append(jobs.Items,
+		newJob("ns1", "job1", "q1", v1alpha1.Failed, 4*time.Hour, 3),
+		newJob("ns2", "job2", "q1", v1alpha1.Running, 3*time.Hour, 1),
+		newJob("ns1", "job3", "q2", v1alpha1.Failed, 2*time.Hour, 2),
+		newJob("ns2", "job4", "q1", v1alpha1.Failed, 1*time.Hour, 0),
+	)
+
+	testCases := []struct {
+		Name       string
+		Phases     []string
+		Queue      string
+		Since      time.Duration
+		SortBy     string
+		ExpectJobs string
+	}{
+		{
+			Name:       "NoFilter",
+			ExpectJobs: "job1,job3,job2,job4",
+		},
+		{
+			Name:       "FailedInQueue",
+			Phases:     []string{"failed"},
+			Queue:      "q1",
+			ExpectJobs: "job1,job4",
+		},
+		{
+			Name:       "CreatedSince",
+			Since:      150 * time.Minute,
+			ExpectJobs: "job3,job4",
+		},
+		{
+			Name:       "SortByCreation",
+			SortBy:     SortByCreation,
+			ExpectJobs: "job4,job3,job2,job1",
+		},
+		{
+			Name:       "SortByDuration",
+			SortBy:     SortByDuration,
+			ExpectJobs: "job2,job1,job3,job4",
+		},
+		{
+			Name:       "SortByRetries",
+			Phases:     []string{"Failed", "Running"},
+			SortBy:     SortByRetries,
+			ExpectJobs: "job1,job3,job2,job4",
+		},
+	}
+
+	for i, testcase := range testCases {
+		listJobFlags.Phases = testcase.Phases
+		listJobFlags.Queue = testcase.Queue
+		listJobFlags.Since = testcase.Since
+
+		result := filterJobs(jobs, now)
+		sortJobs(result, testcase.SortBy, now)
+
+		var names []string
+		for _, job := range result.Items {
+			names = append(names, job.Name)
+		}
+		if strings.Join(names, ",") != testcase.ExpectJobs {
+			t.Errorf("case %d (%s): expected jobs %s, got %v", i, testcase.Name, testcase.ExpectJobs, names)
+		}
+	}
+	listJobFlags.Phases = nil
+	listJobFlags.Queue = ""
+	listJobFlags.Since = 0
 }
@@ -0,0 +1,267 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	vkbatchv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/client/clientset/versioned"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+)
+
+type logsFlags struct {
+	commonFlags
+
+	Namespace string
+	JobName   string
+	TaskName  string
+	Index     int
+	Container string
+
+	Follow            bool
+	Previous          bool
+	PreviousContainer bool
+	Tail              int64
+}
+
+var logsJobFlags = &logsFlags{}
+
+// InitLogsFlags init the logs command flags
+func InitLogsFlags(cmd *cobra.Command) {
+	initFlags(cmd, &logsJobFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&logsJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
+	cmd.Flags().StringVarP(&logsJobFlags.JobName, "name", "n", "", "the name of job")
+	cmd.Flags().StringVarP(&logsJobFlags.TaskName, "task", "t", "", "the name of task, logs of all tasks are printed if not set")
+	cmd.Flags().IntVarP(&logsJobFlags.Index, "index", "i", -1, "the index of pod in task, logs of all pods are printed if not set")
+	cmd.Flags().StringVarP(&logsJobFlags.Container, "container", "c", "", "the container name, the first container of pod is used if not set")
+	cmd.Flags().BoolVarP(&logsJobFlags.Follow, "follow", "f", false, "specify if the logs should be streamed")
+	cmd.Flags().BoolVarP(&logsJobFlags.Previous, "previous", "p", false, "print the logs of the pods left from the last restart of job instead of the current pods")
+	cmd.Flags().BoolVarP(&logsJobFlags.PreviousContainer, "previous-container", "", false, "print the logs of the previous terminated container of each pod")
+	cmd.Flags().Int64VarP(&logsJobFlags.Tail, "tail", "", -1, "lines of recent log of each pod to print, all logs are printed if negative")
+}
+
+// LogsJob prints the logs of all selected pods of the job
+func LogsJob() error {
+	config, err := buildConfig(&logsJobFlags.commonFlags, &logsJobFlags.Namespace)
+	if err != nil {
+		return err
+	}
+	if logsJobFlags.JobName == "" {
+		err := fmt.Errorf("job name (specified by --name or -n) is mandatory to print logs of a particular job")
+		return err
+	}
+
+	jobClient := versioned.NewForConfigOrDie(config)
+	job, err := jobClient.BatchV1alpha1().Jobs(logsJobFlags.Namespace).Get(logsJobFlags.JobName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	pods, err := listJobPods(kubeClient, logsJobFlags.Namespace, logsJobFlags.JobName)
+	if err != nil {
+		return err
+	}
+
+	pods = filterPodsByVersion(pods, job.Status.Version, logsJobFlags.Previous)
+	pods = filterPods(pods, logsJobFlags.TaskName, logsJobFlags.Index)
+	if len(pods) == 0 {
+		fmt.Printf("No pods found\n")
+		return nil
+	}
+
+	return printPodsLogs(kubeClient, pods, os.Stdout)
+}
+
+// listJobPods lists the pods of the job by the job name label, sorted by name
+func listJobPods(kubeClient kubernetes.Interface, namespace, jobName string) ([]v1.Pod, error) {
+	podList, err := kubeClient.CoreV1().Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", vkbatchv1.JobNameKey, jobName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// filterPods selects the pods of the task and index, empty task and negative index select all
+func filterPods(pods []v1.Pod, taskName string, index int) []v1.Pod {
+	var selected []v1.Pod
+	for _, pod := range pods {
+		if taskName != "" && pod.Annotations[vkbatchv1.TaskSpecKey] != taskName {
+			continue
+		}
+		if index >= 0 && jobhelpers.GetTaskIndex(&pod) != strconv.Itoa(index) {
+			continue
+		}
+		selected = append(selected, pod)
+	}
+	return selected
+}
+
+// filterPodsByVersion selects the pods created in the version of job; with previous, it selects
+// the pods left from the last restart of job, i.e. of the latest version below the given one.
+func filterPodsByVersion(pods []v1.Pod, version int32, previous bool) []v1.Pod {
+	versions := make([]int32, len(pods))
+	selected := version
+	if previous {
+		selected = -1
+	}
+	for i := range pods {
+		versions[i] = -1
+		value, err := strconv.ParseInt(pods[i].Annotations[vkbatchv1.JobVersion], 10, 32)
+		if err != nil {
+			continue
+		}
+		versions[i] = int32(value)
+		if previous && versions[i] < version && versions[i] > selected {
+			selected = versions[i]
+		}
+	}
+
+	var result []v1.Pod
+	for i := range pods {
+		if versions[i] >= 0 && versions[i] == selected {
+			result = append(result, pods[i])
+		}
+	}
+	return result
+}
+
+// printPodsLogs prints the logs of pods concurrently, each line is prefixed by task name and index
+func printPodsLogs(kubeClient kubernetes.Interface, pods []v1.Pod, writer io.Writer) error {
+	var (
+		lock sync.Mutex
+		wg   sync.WaitGroup
+		errs []string
+	)
+
+	for i := range pods {
+		wg.Add(1)
+		go func(pod *v1.Pod) {
+			defer wg.Done()
+			if err := printPodLogs(kubeClient, pod, writer, &lock); err != nil {
+				lock.Lock()
+				errs = append(errs, fmt.Sprintf("pod %s: %v", pod.Name, err))
+				lock.Unlock()
+			}
+		}(&pods[i])
+	}
+	wg.Wait()
+
+	if len(errs) != 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("failed to get logs of %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func printPodLogs(kubeClient kubernetes.Interface, pod *v1.Pod, writer io.Writer, lock *sync.Mutex) error {
+	options := &v1.PodLogOptions{
+		Container: logsJobFlags.Container,
+		Follow:    logsJobFlags.Follow,
+		Previous:  logsJobFlags.PreviousContainer,
+	}
+	if options.Container == "" && len(pod.Spec.Containers) != 0 {
+		options.Container = pod.Spec.Containers[0].Name
+	}
+	if logsJobFlags.Tail >= 0 {
+		options.TailLines = &logsJobFlags.Tail
+	}
+
+	stream, err := kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, options).Stream()
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	out := &prefixWriter{
+		prefix: []byte(fmt.Sprintf("[%s-%s] ", pod.Annotations[vkbatchv1.TaskSpecKey], jobhelpers.GetTaskIndex(pod))),
+		writer: writer,
+		lock:   lock,
+	}
+	if _, err := io.Copy(out, stream); err != nil {
+		return err
+	}
+
+	return out.Flush()
+}
+
+// prefixWriter writes the lines prefixed to the writer shared by pods; a partial line is buffered
+// until it is completed or flushed, so the lines of pods are not interleaved.
+type prefixWriter struct {
+	prefix []byte
+	writer io.Writer
+	lock   *sync.Mutex
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered partial line terminated by newline.
+func (w *prefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if _, err := w.writer.Write(w.prefix); err != nil {
+		return err
+	}
+	_, err := w.writer.Write(line)
+	return err
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	v1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func newLogsTestPod(name, task string) v1.Pod {
+	return newVersionedLogsTestPod(name, task, "1")
+}
+
+func newVersionedLogsTestPod(name, task, version string) v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			Annotations: map[string]string{
+				v1alpha1.TaskSpecKey: task,
+				v1alpha1.JobVersion:  version,
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "main"}},
+		},
+	}
+}
+
+func TestLogsJob(t *testing.T) {
+	response := v1.PodList{}
+	response.Items = append(response.Items,
+		newLogsTestPod("testJob-ps-0", "ps"),
+		newLogsTestPod("testJob-worker-0", "worker"),
+		newLogsTestPod("testJob-worker-1", "worker"),
+	)
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "testJob", Namespace: "test"},
+		Status:     v1alpha1.JobStatus{Version: 1},
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/log") {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("line1\nline2\n"))
+			return
+		}
+
+		var obj interface{} = response
+		if strings.HasSuffix(r.URL.Path, "/jobs/testJob") {
+			obj = job
+		}
+		w.Header().Set("Content-Type", "application/json")
+		val, err := json.Marshal(obj)
+		if err == nil {
+			w.Write(val)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	logsJobFlags.Master = server.URL
+	logsJobFlags.Namespace = "test"
+	logsJobFlags.JobName = "testJob"
+	logsJobFlags.Index = -1
+	logsJobFlags.Tail = -1
+
+	if err := LogsJob(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	config, err := buildConfig(&commonFlags{Master: server.URL}, nil)
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+
+	testCases := []struct {
+		Name         string
+		TaskName     string
+		Index        int
+		ExpectOutput string
+	}{
+		{
+			Name:     "AllPods",
+			TaskName: "",
+			Index:    -1,
+			ExpectOutput: "[ps-0] line1\n[ps-0] line2\n" +
+				"[worker-0] line1\n[worker-0] line2\n" +
+				"[worker-1] line1\n[worker-1] line2\n",
+		},
+		{
+			Name:         "TaskPods",
+			TaskName:     "ps",
+			Index:        -1,
+			ExpectOutput: "[ps-0] line1\n[ps-0] line2\n",
+		},
+		{
+			Name:         "TaskIndexPod",
+			TaskName:     "worker",
+			Index:        1,
+			ExpectOutput: "[worker-1] line1\n[worker-1] line2\n",
+		},
+	}
+
+	for i, testcase := range testCases {
+		var buf bytes.Buffer
+		pods := filterPods(response.Items, testcase.TaskName, testcase.Index)
+		if err := printPodsLogs(kubeClient, pods, &buf); err != nil {
+			t.Errorf("case %d (%s): expected no error, got %v", i, testcase.Name, err)
+		}
+
+		// logs of pods are printed concurrently, only the lines of each pod are in order
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		expectLines := strings.Split(strings.TrimSpace(testcase.ExpectOutput), "\n")
+		if !sameLines(lines, expectLines) {
+			t.Errorf("case %d (%s): expected output %q, got %q", i, testcase.Name, testcase.ExpectOutput, buf.String())
+		}
+	}
+}
+
+func sameLines(lines, expectLines []string) bool {
+	if len(lines) != len(expectLines) {
+		return false
+	}
+	count := map[string]int{}
+	for _, line := range lines {
+		count[line]++
+	}
+	for _, line := range expectLines {
+		count[line]--
+		if count[line] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFilterPodsByVersion(t *testing.T) {
+	pods := []v1.Pod{
+		newVersionedLogsTestPod("job-ps-0", "ps", "0"),
+		newVersionedLogsTestPod("job-worker-0", "worker", "1"),
+		newVersionedLogsTestPod("job-worker-1", "worker", "3"),
+		newVersionedLogsTestPod("job-worker-2", "worker", "invalid"),
+	}
+
+	testCases := []struct {
+		Name       string
+		Version    int32
+		Previous   bool
+		ExpectPods []string
+	}{
+		{
+			Name:       "current version",
+			Version:    3,
+			ExpectPods: []string{"job-worker-1"},
+		},
+		{
+			Name:       "last restart version",
+			Version:    3,
+			Previous:   true,
+			ExpectPods: []string{"job-worker-0"},
+		},
+		{
+			Name:     "no previous pods",
+			Version:  0,
+			Previous: true,
+		},
+	}
+
+	for i, testcase := range testCases {
+		var names []string
+		for _, pod := range filterPodsByVersion(pods, testcase.Version, testcase.Previous) {
+			names = append(names, pod.Name)
+		}
+		if !reflect.DeepEqual(names, testcase.ExpectPods) {
+			t.Errorf("case %d (%s): expected pods %v, got %v", i, testcase.Name, testcase.ExpectPods, names)
+		}
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := &prefixWriter{prefix: []byte("[ps-0] "), writer: &buf, lock: &sync.Mutex{}}
+
+	// the line is longer than the default limit of bufio.Scanner
+	long := strings.Repeat("x", 128*1024)
+	input := "line1\n" + long + "\nlast"
+	if _, err := io.Copy(writer, strings.NewReader(input)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "[ps-0] line1\n[ps-0] " + long + "\n[ps-0] last\n"
+	if buf.String() != expected {
+		t.Errorf("expected %d bytes of prefixed lines, got %d bytes", len(expected), buf.Len())
+	}
+}
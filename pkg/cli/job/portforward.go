@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+type portForwardFlags struct {
+	commonFlags
+
+	Namespace string
+	JobName   string
+	TaskName  string
+	Index     int
+	Address   []string
+}
+
+var portForwardJobFlags = &portForwardFlags{}
+
+// InitPortForwardFlags init the port-forward command flags
+func InitPortForwardFlags(cmd *cobra.Command) {
+	initFlags(cmd, &portForwardJobFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&portForwardJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
+	cmd.Flags().StringVarP(&portForwardJobFlags.JobName, "name", "n", "", "the name of job, instead of the first argument")
+	cmd.Flags().StringVarP(&portForwardJobFlags.TaskName, "task", "t", "", "the name of task")
+	cmd.Flags().IntVarP(&portForwardJobFlags.Index, "index", "i", 0, "the index of pod in task")
+	cmd.Flags().StringSliceVarP(&portForwardJobFlags.Address, "address", "", []string{"localhost"},
+		"addresses to listen on (comma separated)")
+}
+
+// PortForwardJob forwards the local ports to the pod of the task, the first of args is the job unless it is
+// specified by --name, the others are the ports in the form of [LOCAL_PORT:]REMOTE_PORT
+func PortForwardJob(args []string) error {
+	config, err := buildConfig(&portForwardJobFlags.commonFlags, &portForwardJobFlags.Namespace)
+	if err != nil {
+		return err
+	}
+
+	jobName, ports := jobNameFromArgs(portForwardJobFlags.JobName, args)
+	if jobName == "" {
+		err := fmt.Errorf("job name (specified by the first argument or --name) is mandatory to forward ports to a particular job")
+		return err
+	}
+	if len(ports) == 0 {
+		err := fmt.Errorf("at least one port is mandatory, e.g. vkctl job port-forward job -t task 6006")
+		return err
+	}
+
+	pod, err := getTaskPod(config, portForwardJobFlags.Namespace, jobName,
+		portForwardJobFlags.TaskName, portForwardJobFlags.Index)
+	if err != nil {
+		return err
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		return fmt.Errorf("cannot forward ports to pod %s/%s, it is %s", pod.Namespace, pod.Name, pod.Status.Phase)
+	}
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	req := kubeClient.CoreV1().RESTClient().Post().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return err
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	// The ports are forwarded until interrupted.
+	stopCh := make(chan struct{})
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt)
+	defer signal.Stop(signalCh)
+	go func() {
+		<-signalCh
+		close(stopCh)
+	}()
+
+	forwarder, err := portforward.NewOnAddresses(dialer, portForwardJobFlags.Address, ports,
+		stopCh, make(chan struct{}), os.Stdout, os.Stderr)
+	if err != nil {
+		return err
+	}
+
+	return forwarder.ForwardPorts()
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"net/http"
+	"testing"
+
+	"k8s.io/api/core/v1"
+)
+
+func TestPortForwardJob(t *testing.T) {
+	portForwardJobFlags.Namespace = "test"
+	portForwardJobFlags.Index = 3
+	portForwardJobFlags.Address = []string{"localhost"}
+	portForwardJobFlags.AsUser = "alice"
+
+	testCases := []struct {
+		Name          string
+		JobName       string
+		TaskName      string
+		Phase         v1.PodPhase
+		Args          []string
+		ExpectForward bool
+	}{
+		{
+			Name:          "PortForwardJob",
+			TaskName:      "worker",
+			Phase:         v1.PodRunning,
+			Args:          []string{"testjob", "0:80"},
+			ExpectForward: true,
+		},
+		{
+			Name:          "JobNameFlag",
+			JobName:       "testjob",
+			TaskName:      "worker",
+			Phase:         v1.PodRunning,
+			Args:          []string{"0:80"},
+			ExpectForward: true,
+		},
+		{
+			Name:     "PodNotRunning",
+			TaskName: "worker",
+			Phase:    v1.PodPending,
+			Args:     []string{"testjob", "0:80"},
+		},
+		{
+			Name:     "NoTask",
+			TaskName: "",
+			Phase:    v1.PodRunning,
+			Args:     []string{"testjob", "0:80"},
+		},
+		{
+			Name:     "NoPort",
+			TaskName: "worker",
+			Phase:    v1.PodRunning,
+			Args:     []string{"testjob"},
+		},
+	}
+
+	for i, testcase := range testCases {
+		var streamRequest *http.Request
+		server := newStreamingServer(testcase.Phase, &streamRequest)
+		portForwardJobFlags.Master = server.URL
+		portForwardJobFlags.JobName = testcase.JobName
+		portForwardJobFlags.TaskName = testcase.TaskName
+
+		// The ports are not forwarded since the test server does not upgrade the connection.
+		if err := PortForwardJob(testcase.Args); err == nil {
+			t.Errorf("case %d (%s): expected error, got nil", i, testcase.Name)
+		}
+		server.Close()
+
+		if !testcase.ExpectForward {
+			if streamRequest != nil {
+				t.Errorf("case %d (%s): expected no port-forward request, got %s", i, testcase.Name, streamRequest.URL)
+			}
+			continue
+		}
+		if streamRequest == nil || streamRequest.URL.Path != "/api/v1/namespaces/test/pods/testjob-worker-3/portforward" {
+			t.Errorf("case %d (%s): expected port-forward request of pod testjob-worker-3, got %v", i, testcase.Name, streamRequest)
+			continue
+		}
+		if upgrade := streamRequest.Header.Get("Upgrade"); upgrade != "SPDY/3.1" {
+			t.Errorf("case %d (%s): expected port-forward request upgraded to SPDY/3.1, got %q", i, testcase.Name, upgrade)
+		}
+		if user := streamRequest.Header.Get("Impersonate-User"); user != "alice" {
+			t.Errorf("case %d (%s): expected port-forward request as alice, got %q", i, testcase.Name, user)
+		}
+	}
+	portForwardJobFlags.JobName = ""
+	portForwardJobFlags.AsUser = ""
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+type restartFlags struct {
+	commonFlags
+
+	Namespace string
+	JobName   string
+	TaskName  string
+	Reason    string
+	Message   string
+}
+
+var restartJobFlags = &restartFlags{}
+
+// InitRestartFlags init restart related flags
+func InitRestartFlags(cmd *cobra.Command) {
+	initFlags(cmd, &restartJobFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&restartJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
+	cmd.Flags().StringVarP(&restartJobFlags.JobName, "name", "n", "", "the name of job")
+	cmd.Flags().StringVarP(&restartJobFlags.TaskName, "task", "t", "",
+		"the name of task to restart; only the pods of the task are deleted and created again by the job controller")
+	cmd.Flags().StringVarP(&restartJobFlags.Reason, "reason", "", "", "the one-word, CamelCase reason of restarting job")
+	cmd.Flags().StringVarP(&restartJobFlags.Message, "message", "", "", "the human-readable message of restarting job")
+}
+
+// RestartJob restarts the job, or the task of the job if task name is set
+func RestartJob() error {
+	config, err := buildConfig(&restartJobFlags.commonFlags, &restartJobFlags.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if restartJobFlags.JobName == "" {
+		err := fmt.Errorf("job name is mandatory to restart a particular job")
+		return err
+	}
+
+	// Only the pods of task are restarted by the job controller if task name is set.
+	action := v1alpha1.RestartJobAction
+	if restartJobFlags.TaskName != "" {
+		action = v1alpha1.RestartTaskAction
+	}
+
+	return createTaskCommand(config,
+		restartJobFlags.Namespace, restartJobFlags.JobName, restartJobFlags.TaskName,
+		action, restartJobFlags.Reason, restartJobFlags.Message)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1batch "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	v1alpha1 "volcano.sh/volcano/pkg/apis/bus/v1alpha1"
+)
+
+func TestRestartJob(t *testing.T) {
+	responsejob := v1alpha1batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testjob",
+			Namespace: "test",
+		},
+		Spec: v1alpha1batch.JobSpec{
+			Tasks: []v1alpha1batch.TaskSpec{{Name: "worker"}},
+		},
+	}
+
+	var command *v1alpha1.Command
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var response interface{}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "commands"):
+			command = &v1alpha1.Command{}
+			body, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(body, command)
+			response = command
+		default:
+			response = responsejob
+		}
+		val, err := json.Marshal(response)
+		if err == nil {
+			w.Write(val)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	restartJobFlags.Master = server.URL
+	restartJobFlags.Namespace = "test"
+	restartJobFlags.JobName = "testjob"
+	restartJobFlags.Reason = "OutOfMemory"
+	restartJobFlags.Message = "restart with bigger batch size"
+
+	testCases := []struct {
+		Name         string
+		TaskName     string
+		ExpectError  bool
+		ExpectAction v1alpha1batch.Action
+	}{
+		{
+			Name:         "RestartJob",
+			ExpectAction: v1alpha1batch.RestartJobAction,
+		},
+		{
+			Name:         "RestartTask",
+			TaskName:     "worker",
+			ExpectAction: v1alpha1batch.RestartTaskAction,
+		},
+		{
+			Name:        "RestartNotExistTask",
+			TaskName:    "ps",
+			ExpectError: true,
+		},
+	}
+
+	for i, testcase := range testCases {
+		command = nil
+		restartJobFlags.TaskName = testcase.TaskName
+
+		err := RestartJob()
+		if (err != nil) != testcase.ExpectError {
+			t.Errorf("case %d (%s): expected error: %v, got %v ", i, testcase.Name, testcase.ExpectError, err)
+		}
+		if testcase.ExpectError {
+			if command != nil {
+				t.Errorf("case %d (%s): expected no command, got %v", i, testcase.Name, command)
+			}
+			continue
+		}
+		if command == nil || command.Action != string(testcase.ExpectAction) ||
+			command.Annotations[v1alpha1batch.TaskSpecKey] != testcase.TaskName ||
+			command.Reason != restartJobFlags.Reason || command.Message != restartJobFlags.Message {
+			t.Errorf("case %d (%s): expected command %s of task %q with reason %s and message %s, got %v",
+				i, testcase.Name, testcase.ExpectAction, testcase.TaskName,
+				restartJobFlags.Reason, restartJobFlags.Message, command)
+		}
+	}
+	restartJobFlags.TaskName = ""
+}
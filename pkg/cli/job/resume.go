@@ -37,13 +37,13 @@ var resumeJobFlags = &resumeFlags{}
 func InitResumeFlags(cmd *cobra.Command) {
 	initFlags(cmd, &resumeJobFlags.commonFlags)
 
-	cmd.Flags().StringVarP(&resumeJobFlags.Namespace, "namespace", "N", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&resumeJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
 	cmd.Flags().StringVarP(&resumeJobFlags.JobName, "name", "n", "", "the name of job")
 }
 
 // ResumeJob  resumes the job
 func ResumeJob() error {
-	config, err := buildConfig(resumeJobFlags.Master, resumeJobFlags.Kubeconfig)
+	config, err := buildConfig(&resumeJobFlags.commonFlags, &resumeJobFlags.Namespace)
 	if err != nil {
 		return err
 	}
@@ -54,5 +54,5 @@ func ResumeJob() error {
 
 	return createJobCommand(config,
 		resumeJobFlags.Namespace, resumeJobFlags.JobName,
-		v1alpha1.ResumeJobAction)
+		v1alpha1.ResumeJobAction, "", "")
 }
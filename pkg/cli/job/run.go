@@ -17,13 +17,29 @@ limitations under the License.
 package job
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 	"github.com/spf13/cobra"
 
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
 
+	"volcano.sh/volcano/pkg/admission"
 	vkapi "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/cli/util"
 	"volcano.sh/volcano/pkg/client/clientset/versioned"
+	"volcano.sh/volcano/pkg/controllers/job/dryrun"
 )
 
 type runFlags struct {
@@ -38,6 +54,10 @@ type runFlags struct {
 	Requests      string
 	Limits        string
 	SchedulerName string
+
+	FileName string
+	Values   []string
+	DryRun   bool
 }
 
 var launchJobFlags = &runFlags{}
@@ -47,33 +67,199 @@ func InitRunFlags(cmd *cobra.Command) {
 	initFlags(cmd, &launchJobFlags.commonFlags)
 
 	cmd.Flags().StringVarP(&launchJobFlags.Image, "image", "i", "busybox", "the container image of job")
-	cmd.Flags().StringVarP(&launchJobFlags.Namespace, "namespace", "N", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&launchJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
 	cmd.Flags().StringVarP(&launchJobFlags.Name, "name", "n", "test", "the name of job")
 	cmd.Flags().IntVarP(&launchJobFlags.MinAvailable, "min", "m", 1, "the minimal available tasks of job")
 	cmd.Flags().IntVarP(&launchJobFlags.Replicas, "replicas", "r", 1, "the total tasks of job")
 	cmd.Flags().StringVarP(&launchJobFlags.Requests, "requests", "R", "cpu=1000m,memory=100Mi", "the resource request of the task")
 	cmd.Flags().StringVarP(&launchJobFlags.Limits, "limits", "L", "cpu=1000m,memory=100Mi", "the resource limit of the task")
 	cmd.Flags().StringVarP(&listJobFlags.SchedulerName, "scheduler", "S", "kube-batch", "the scheduler for this job")
+	cmd.Flags().StringVarP(&launchJobFlags.FileName, "filename", "f", "", "the yaml file of job, other job flags are ignored if set")
+	cmd.Flags().StringArrayVarP(&launchJobFlags.Values, "set", "", nil,
+		"the value used in the job file template, in the form of key=value; can be set multiple times")
+	cmd.Flags().BoolVarP(&launchJobFlags.DryRun, "dry-run", "", false,
+		"print the pods, PodGroup, PVCs, Services and ConfigMaps the job expands to without creating the job; "+
+			"the job is defaulted and validated by admission with a server-side dry run first")
 }
 
 var jobName = "job.volcano.sh"
 
 // RunJob  creates the job
 func RunJob() error {
-	config, err := buildConfig(launchJobFlags.Master, launchJobFlags.Kubeconfig)
+	config, err := buildConfig(&launchJobFlags.commonFlags, &launchJobFlags.Namespace)
 	if err != nil {
 		return err
 	}
 
-	req, err := populateResourceListV1(launchJobFlags.Requests)
+	var job *vkapi.Job
+	if launchJobFlags.FileName != "" {
+		if job, err = readFile(launchJobFlags.FileName, launchJobFlags.Values); err != nil {
+			return err
+		}
+		if job.Namespace == "" {
+			job.Namespace = launchJobFlags.Namespace
+		}
+		if err := validateJob(job); err != nil {
+			return err
+		}
+	} else {
+		if job, err = constructLaunchJobFlagsJob(); err != nil {
+			return err
+		}
+	}
+
+	if launchJobFlags.DryRun {
+		return dryRunJob(config, job, os.Stdout)
+	}
+
+	jobClient := versioned.NewForConfigOrDie(config)
+	newJob, err := jobClient.BatchV1alpha1().Jobs(job.Namespace).Create(job)
 	if err != nil {
 		return err
 	}
 
-	limit, err := populateResourceListV1(launchJobFlags.Limits)
+	fmt.Printf("run job %v successfully\n", newJob.Name)
+
+	return nil
+}
+
+// dryRunJob creates the job with a server-side dry run, so it is defaulted and validated by admission
+// without being persisted, and prints the resources the defaulted job expands to.
+func dryRunJob(config *rest.Config, job *vkapi.Job, w io.Writer) error {
+	jobClient := versioned.NewForConfigOrDie(config)
+	defaulted := &vkapi.Job{}
+	if err := jobClient.BatchV1alpha1().RESTClient().Post().
+		Namespace(job.Namespace).
+		Resource("jobs").
+		Param("dryRun", metav1.DryRunAll).
+		Body(job).
+		Do().
+		Into(defaulted); err != nil {
+		return err
+	}
+
+	result, err := dryrun.Job(kubernetes.NewForConfigOrDie(config), defaulted)
 	if err != nil {
 		return err
 	}
+	return printDryRunResult(w, result)
+}
+
+// printDryRunResult prints the resources of job dry run as a yaml stream.
+func printDryRunResult(w io.Writer, result *dryrun.Result) error {
+	var objects []interface{}
+
+	result.PodGroup.TypeMeta = metav1.TypeMeta{APIVersion: kbv1.SchemeGroupVersion.String(), Kind: "PodGroup"}
+	objects = append(objects, result.PodGroup)
+	for _, pvc := range result.PVCs {
+		pvc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"}
+		objects = append(objects, pvc)
+	}
+	for _, svc := range result.Services {
+		svc.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+		objects = append(objects, svc)
+	}
+	for _, cm := range result.ConfigMaps {
+		cm.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"}
+		objects = append(objects, cm)
+	}
+	for _, pod := range result.Pods {
+		pod.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"}
+		objects = append(objects, pod)
+	}
+
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readFile reads the job from the yaml file, the file is rendered as a Go template with the values first
+func readFile(filename string, values []string) (*vkapi.Job, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %v", filename, err)
+	}
+
+	data, err := parseValues(values)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(filename).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %v", filename, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render file %s: %v", filename, err)
+	}
+
+	job := &vkapi.Job{}
+	if err := yaml.UnmarshalStrict(buf.Bytes(), job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal file %s: %v", filename, err)
+	}
+
+	return job, nil
+}
+
+// parseValues takes strings of form <key>=<value> and returns them as template data;
+// integer and boolean values are converted, so they can be used in template actions.
+func parseValues(values []string) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid argument syntax %v, expected <key>=<value>", value)
+		}
+
+		if i, err := strconv.Atoi(parts[1]); err == nil {
+			data[parts[0]] = i
+		} else if b, err := strconv.ParseBool(parts[1]); err == nil {
+			data[parts[0]] = b
+		} else {
+			data[parts[0]] = parts[1]
+		}
+	}
+	return data, nil
+}
+
+// validateJob validates the job by the same rules as admission controller, after setting the
+// defaults of admission controller
+func validateJob(job *vkapi.Job) error {
+	if job.Spec.Queue == "" {
+		job.Spec.Queue = admission.DefaultQueue
+	}
+	for index := range job.Spec.Tasks {
+		if job.Spec.Tasks[index].Name == "" {
+			job.Spec.Tasks[index].Name = vkapi.DefaultTaskSpec + strconv.Itoa(index)
+		}
+	}
+
+	if allErrs := admission.ValidateJobSpec(*job); len(allErrs) != 0 {
+		return fmt.Errorf("job %s is invalid: %v", job.Name, allErrs.ToAggregate())
+	}
+
+	return nil
+}
+
+func constructLaunchJobFlagsJob() (*vkapi.Job, error) {
+	req, err := util.PopulateResourceListV1(launchJobFlags.Requests)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := util.PopulateResourceListV1(launchJobFlags.Limits)
+	if err != nil {
+		return nil, err
+	}
 
 	job := &vkapi.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -112,10 +298,5 @@ func RunJob() error {
 		},
 	}
 
-	jobClient := versioned.NewForConfigOrDie(config)
-	if _, err := jobClient.BatchV1alpha1().Jobs(launchJobFlags.Namespace).Create(job); err != nil {
-		return err
-	}
-
-	return nil
+	return job, nil
 }
@@ -17,12 +17,21 @@ limitations under the License.
 package job
 
 import (
+	"bytes"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"k8s.io/client-go/kubernetes/fake"
+
 	v1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/controllers/job/dryrun"
 )
 
 func TestCreateJob(t *testing.T) {
@@ -61,3 +70,156 @@ func TestCreateJob(t *testing.T) {
 	}
 
 }
+
+var jobTemplate = `apiVersion: batch.volcano.sh/v1alpha1
+kind: Job
+metadata:
+  name: {{ .name }}
+spec:
+  minAvailable: {{ .workers }}
+  tasks:
+  - name: ps
+    replicas: 1
+    template:
+      spec:
+        containers:
+        - name: ps
+          image: {{ .image }}
+        restartPolicy: Never
+  - name: worker
+    replicas: {{ .workers }}
+    template:
+      spec:
+        containers:
+        - name: worker
+          image: {{ .image }}
+        restartPolicy: Never
+`
+
+func TestCreateJobFromFile(t *testing.T) {
+	response := v1alpha1.Job{}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		val, err := json.Marshal(response)
+		if err == nil {
+			w.Write(val)
+		}
+
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "vkctl-run")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "job.yaml")
+	if err := ioutil.WriteFile(fileName, []byte(jobTemplate), 0644); err != nil {
+		t.Fatalf("failed to write job file: %v", err)
+	}
+
+	launchJobFlags.Master = server.URL
+	launchJobFlags.Namespace = "test"
+	launchJobFlags.FileName = fileName
+	defer func() { launchJobFlags.FileName = "" }()
+
+	testCases := []struct {
+		Name        string
+		Values      []string
+		ExpectError bool
+	}{
+		{
+			Name:        "CreateJobFromFile",
+			Values:      []string{"name=test", "image=busybox", "workers=2"},
+			ExpectError: false,
+		},
+		{
+			Name:        "MissingValue",
+			Values:      []string{"name=test", "workers=2"},
+			ExpectError: true,
+		},
+		{
+			Name:        "InvalidValue",
+			Values:      []string{"name", "image=busybox", "workers=2"},
+			ExpectError: true,
+		},
+		{
+			Name:        "InvalidJob",
+			Values:      []string{"name=test", "image=busybox", "workers=0"},
+			ExpectError: true,
+		},
+	}
+
+	for i, testcase := range testCases {
+		launchJobFlags.Values = testcase.Values
+
+		err := RunJob()
+		if (err != nil) != testcase.ExpectError {
+			t.Errorf("case %d (%s): expected error: %v, got %v ", i, testcase.Name, testcase.ExpectError, err)
+		}
+	}
+}
+
+func TestRunJobDryRun(t *testing.T) {
+	var dryRunQuery url.Values
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/apis/batch.volcano.sh/v1alpha1/namespaces/test/jobs" {
+			t.Errorf("unexpected request %s %s in dry run", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		dryRunQuery = r.URL.Query()
+
+		// The job is returned with the defaults set by admission.
+		job := v1alpha1.Job{}
+		if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+			t.Errorf("failed to decode job: %v", err)
+		}
+		job.Spec.Queue = "default"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(job)
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	launchJobFlags.Master = server.URL
+	launchJobFlags.Namespace = "test"
+	launchJobFlags.DryRun = true
+	defer func() { launchJobFlags.DryRun = false }()
+
+	if err := RunJob(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if dryRun := dryRunQuery.Get("dryRun"); dryRun != "All" {
+		t.Errorf("expected job created with dryRun=All, got %q", dryRun)
+	}
+}
+
+func TestPrintDryRunResult(t *testing.T) {
+	job, err := constructLaunchJobFlagsJob()
+	if err != nil {
+		t.Fatalf("failed to construct job: %v", err)
+	}
+	job.Spec.Plugins = map[string][]string{"svc": {}}
+
+	result, err := dryrun.Job(fake.NewSimpleClientset(), job)
+	if err != nil {
+		t.Fatalf("failed to dry run job: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := printDryRunResult(&buf, result); err != nil {
+		t.Fatalf("failed to print dry run result: %v", err)
+	}
+	output := buf.String()
+	for _, kind := range []string{"kind: PodGroup", "kind: Service", "kind: ConfigMap", "kind: Pod"} {
+		if !strings.Contains(output, kind) {
+			t.Errorf("expected %q in output:\n%s", kind, output)
+		}
+	}
+}
@@ -36,13 +36,13 @@ var suspendJobFlags = &suspendFlags{}
 func InitSuspendFlags(cmd *cobra.Command) {
 	initFlags(cmd, &suspendJobFlags.commonFlags)
 
-	cmd.Flags().StringVarP(&suspendJobFlags.Namespace, "namespace", "N", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&suspendJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
 	cmd.Flags().StringVarP(&suspendJobFlags.JobName, "name", "n", "", "the name of job")
 }
 
 // SuspendJob  suspends the job
 func SuspendJob() error {
-	config, err := buildConfig(suspendJobFlags.Master, suspendJobFlags.Kubeconfig)
+	config, err := buildConfig(&suspendJobFlags.commonFlags, &suspendJobFlags.Namespace)
 	if err != nil {
 		return err
 	}
@@ -54,5 +54,5 @@ func SuspendJob() error {
 
 	return createJobCommand(config,
 		suspendJobFlags.Namespace, suspendJobFlags.JobName,
-		v1alpha1.AbortJobAction)
+		v1alpha1.AbortJobAction, "", "")
 }
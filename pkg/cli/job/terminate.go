@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+type terminateFlags struct {
+	commonFlags
+
+	Namespace string
+	JobName   string
+	Reason    string
+	Message   string
+}
+
+var terminateJobFlags = &terminateFlags{}
+
+// InitTerminateFlags init terminate related flags
+func InitTerminateFlags(cmd *cobra.Command) {
+	initFlags(cmd, &terminateJobFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&terminateJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
+	cmd.Flags().StringVarP(&terminateJobFlags.JobName, "name", "n", "", "the name of job")
+	cmd.Flags().StringVarP(&terminateJobFlags.Reason, "reason", "", "", "the one-word, CamelCase reason of terminating job")
+	cmd.Flags().StringVarP(&terminateJobFlags.Message, "message", "", "", "the human-readable message of terminating job")
+}
+
+// TerminateJob terminates the job
+func TerminateJob() error {
+	config, err := buildConfig(&terminateJobFlags.commonFlags, &terminateJobFlags.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if terminateJobFlags.JobName == "" {
+		err := fmt.Errorf("job name is mandatory to terminate a particular job")
+		return err
+	}
+
+	return createJobCommand(config,
+		terminateJobFlags.Namespace, terminateJobFlags.JobName,
+		v1alpha1.TerminateJobAction, terminateJobFlags.Reason, terminateJobFlags.Message)
+}
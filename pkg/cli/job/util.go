@@ -18,77 +18,79 @@ package job
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 
 	vkbatchv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	vkbusv1 "volcano.sh/volcano/pkg/apis/bus/v1alpha1"
 	"volcano.sh/volcano/pkg/apis/helpers"
+	"volcano.sh/volcano/pkg/cli/util"
 	"volcano.sh/volcano/pkg/client/clientset/versioned"
+	jobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 )
 
-func homeDir() string {
-	if h := os.Getenv("HOME"); h != "" {
-		return h
-	}
-	return os.Getenv("USERPROFILE") // windows
-}
-
-func buildConfig(master, kubeconfig string) (*rest.Config, error) {
-	return clientcmd.BuildConfigFromFlags(master, kubeconfig)
-}
-
-// populateResourceListV1 takes strings of form <resourceName1>=<value1>,<resourceName1>=<value2>
-// and returns ResourceList.
-func populateResourceListV1(spec string) (v1.ResourceList, error) {
-	// empty input gets a nil response to preserve generator test expected behaviors
-	if spec == "" {
-		return nil, nil
-	}
-
-	result := v1.ResourceList{}
-	resourceStatements := strings.Split(spec, ",")
-	for _, resourceStatement := range resourceStatements {
-		parts := strings.Split(resourceStatement, "=")
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid argument syntax %v, expected <resource>=<value>", resourceStatement)
-		}
-		resourceName := v1.ResourceName(parts[0])
-		resourceQuantity, err := resource.ParseQuantity(parts[1])
+// buildConfig builds the config to access apiserver; if namespace is empty, it is set to the
+// namespace of current kubeconfig context.
+func buildConfig(cf *commonFlags, namespace *string) (*rest.Config, error) {
+	clientConfig := util.ClientConfig(cf.Master, cf.Kubeconfig, cf.Context, cf.AsUser, cf.AsGroups)
+	if namespace != nil && *namespace == "" {
+		ns, _, err := clientConfig.Namespace()
 		if err != nil {
 			return nil, err
 		}
-		result[resourceName] = resourceQuantity
+		*namespace = ns
 	}
-	return result, nil
+
+	return clientConfig.ClientConfig()
+}
+
+func createJobCommand(config *rest.Config, ns, name string, action vkbatchv1.Action, reason, message string) error {
+	return createTaskCommand(config, ns, name, "", action, reason, message)
 }
 
-func createJobCommand(config *rest.Config, ns, name string, action vkbatchv1.Action) error {
+// createTaskCommand creates the command of action on the task of job, the task is ignored if empty
+func createTaskCommand(config *rest.Config, ns, name, taskName string, action vkbatchv1.Action, reason, message string) error {
 	jobClient := versioned.NewForConfigOrDie(config)
 	job, err := jobClient.BatchV1alpha1().Jobs(ns).Get(name, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
+	var annotations map[string]string
+	if taskName != "" {
+		found := false
+		for _, task := range job.Spec.Tasks {
+			if task.Name == taskName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no task %s found in job %s/%s", taskName, ns, name)
+		}
+		annotations = map[string]string{vkbatchv1.TaskSpecKey: taskName}
+	}
+
 	ctrlRef := metav1.NewControllerRef(job, helpers.JobKind)
 	cmd := &vkbusv1.Command{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: fmt.Sprintf("%s-%s-",
 				job.Name, strings.ToLower(string(action))),
-			Namespace: job.Namespace,
+			Namespace:   job.Namespace,
+			Annotations: annotations,
 			OwnerReferences: []metav1.OwnerReference{
 				*ctrlRef,
 			},
 		},
 		TargetObject: ctrlRef,
 		Action:       string(action),
+		Reason:       reason,
+		Message:      message,
 	}
 
 	if _, err := jobClient.BusV1alpha1().Commands(ns).Create(cmd); err != nil {
@@ -97,3 +99,32 @@ func createJobCommand(config *rest.Config, ns, name string, action vkbatchv1.Act
 
 	return nil
 }
+
+// jobNameFromArgs returns the name of job and the rest of args, the job is the first of args unless it is
+// specified by --name
+func jobNameFromArgs(name string, args []string) (string, []string) {
+	if name != "" || len(args) == 0 {
+		return name, args
+	}
+	return args[0], args[1:]
+}
+
+// getTaskPod returns the pod of the task with the index in job
+func getTaskPod(config *rest.Config, ns, jobName, taskName string, index int) (*v1.Pod, error) {
+	if taskName == "" {
+		return nil, fmt.Errorf("task name (specified by --task or -t) is mandatory to find the pod of job")
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("index of task can not be negative")
+	}
+
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	podName := jobhelpers.MakePodName(jobName, taskName, index)
+	pod, err := kubeClient.CoreV1().Pods(ns).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod of task %s with index %d in job %s/%s: %v",
+			taskName, index, ns, jobName, err)
+	}
+
+	return pod, nil
+}
@@ -24,9 +24,18 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	kbver "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
 
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/cli/util"
 	"volcano.sh/volcano/pkg/client/clientset/versioned"
 )
 
@@ -35,6 +44,8 @@ type viewFlags struct {
 
 	Namespace string
 	JobName   string
+	Watch     bool
+	Output    string
 }
 
 var viewJobFlags = &viewFlags{}
@@ -43,13 +54,15 @@ var viewJobFlags = &viewFlags{}
 func InitViewFlags(cmd *cobra.Command) {
 	initFlags(cmd, &viewJobFlags.commonFlags)
 
-	cmd.Flags().StringVarP(&viewJobFlags.Namespace, "namespace", "N", "default", "the namespace of job")
+	cmd.Flags().StringVarP(&viewJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
 	cmd.Flags().StringVarP(&viewJobFlags.JobName, "name", "n", "", "the name of job")
+	cmd.Flags().BoolVarP(&viewJobFlags.Watch, "watch", "w", false, "watch the job and redraw it on change")
+	cmd.Flags().StringVarP(&viewJobFlags.Output, "output", "o", "", util.OutputUsage)
 }
 
 // ViewJob gives full details of the  job
 func ViewJob() error {
-	config, err := buildConfig(viewJobFlags.Master, viewJobFlags.Kubeconfig)
+	config, err := buildConfig(&viewJobFlags.commonFlags, &viewJobFlags.Namespace)
 	if err != nil {
 		return err
 	}
@@ -58,6 +71,18 @@ func ViewJob() error {
 		return err
 	}
 
+	if err := util.ValidateOutput(viewJobFlags.Output); err != nil {
+		return err
+	}
+
+	if viewJobFlags.Watch {
+		// The jobs are redrawn as tables on change, other output formats are not streamed.
+		if !util.IsTableOutput(viewJobFlags.Output) {
+			return fmt.Errorf("output format %s is not supported with --watch", viewJobFlags.Output)
+		}
+		return watchJobs(config, viewJobFlags.Namespace, viewJobFlags.JobName, "", os.Stdout, wait.NeverStop)
+	}
+
 	jobClient := versioned.NewForConfigOrDie(config)
 	job, err := jobClient.BatchV1alpha1().Jobs(viewJobFlags.Namespace).Get(viewJobFlags.JobName, metav1.GetOptions{})
 	if err != nil {
@@ -67,7 +92,41 @@ func ViewJob() error {
 		fmt.Printf("No resources found\n")
 		return nil
 	}
-	PrintJob(job, os.Stdout)
+	if !util.IsTableOutput(viewJobFlags.Output) {
+		return util.PrintObject(viewJobFlags.Output, JobResource, job, os.Stdout)
+	}
+
+	events, err := getJobEvents(kubernetes.NewForConfigOrDie(config), job)
+	if err != nil {
+		return err
+	}
+
+	return printJobDetails(config, job, events, os.Stdout)
+}
+
+// printJobDetails prints the job, together with its pods grouped by task, PodGroup conditions and
+// recent events, which explain why a job is not running
+func printJobDetails(config *rest.Config, job *v1alpha1.Job, events []v1.Event, writer io.Writer) error {
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+	kbClient := kbver.NewForConfigOrDie(config)
+
+	pods, err := listJobPods(kubeClient, job.Namespace, job.Name)
+	if err != nil {
+		return err
+	}
+
+	pg, err := kbClient.SchedulingV1alpha1().PodGroups(job.Namespace).Get(job.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		pg = nil
+	}
+
+	PrintJob(job, writer)
+	PrintTaskPods(job, pods, writer)
+	PrintPodGroupConditions(pg, writer)
+	PrintEvents(events, writer)
 
 	return nil
 }
@@ -99,3 +158,73 @@ func PrintJob(job *v1alpha1.Job, writer io.Writer) {
 		fmt.Printf("Failed to print view command result: %s.\n", err)
 	}
 }
+
+// PrintTaskPods prints the pods of job grouped by task
+func PrintTaskPods(job *v1alpha1.Job, pods []v1.Pod, writer io.Writer) {
+	taskPods := map[string][]v1.Pod{}
+	maxNameLen := len(Name)
+	for _, pod := range pods {
+		taskName := pod.Annotations[v1alpha1.TaskSpecKey]
+		taskPods[taskName] = append(taskPods[taskName], pod)
+		if len(pod.Name) > maxNameLen {
+			maxNameLen = len(pod.Name)
+		}
+	}
+	format := fmt.Sprintf("    %%-%ds%%-14s%%-25s%%v", maxNameLen+3)
+
+	lines := []string{"Tasks:"}
+	for _, task := range job.Spec.Tasks {
+		lines = append(lines, fmt.Sprintf("  %s:", task.Name))
+		if len(taskPods[task.Name]) == 0 {
+			lines = append(lines, "    <none>")
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf(format, Name, Phase, Node, Restarts))
+		for _, pod := range taskPods[task.Name] {
+			var restarts int32
+			for _, status := range pod.Status.ContainerStatuses {
+				restarts += status.RestartCount
+			}
+			phase := string(pod.Status.Phase)
+			if pod.DeletionTimestamp != nil {
+				phase = Terminating
+			}
+			nodeName := pod.Spec.NodeName
+			if nodeName == "" {
+				nodeName = "<none>"
+			}
+			lines = append(lines, fmt.Sprintf(format, pod.Name, phase, nodeName, restarts))
+		}
+	}
+
+	_, err := fmt.Fprint(writer, strings.Join(lines, "\n"), "\n")
+	if err != nil {
+		fmt.Printf("Failed to print view command result: %s.\n", err)
+	}
+}
+
+// PrintPodGroupConditions prints the phase and conditions of the PodGroup of job
+func PrintPodGroupConditions(pg *kbv1.PodGroup, writer io.Writer) {
+	lines := []string{"PodGroup:"}
+	if pg == nil {
+		lines = append(lines, "  <none>")
+	} else {
+		lines = append(lines, fmt.Sprintf("  %s:\t%s", Phase, pg.Status.Phase))
+		if len(pg.Status.Conditions) == 0 {
+			lines = append(lines, "  Conditions:\t<none>")
+		} else {
+			lines = append(lines, "  Conditions:")
+			lines = append(lines, fmt.Sprintf("    %-18s%-8s%-22s%-25s%s", "Type", "Status", "Reason", "Last Transition", "Message"))
+			for _, cond := range pg.Status.Conditions {
+				lines = append(lines, fmt.Sprintf("    %-18s%-8s%-22s%-25s%s", cond.Type, cond.Status, cond.Reason,
+					cond.LastTransitionTime.Format("2006-01-02 15:04:05"), cond.Message))
+			}
+		}
+	}
+
+	_, err := fmt.Fprint(writer, strings.Join(lines, "\n"), "\n")
+	if err != nil {
+		fmt.Printf("Failed to print view command result: %s.\n", err)
+	}
+}
@@ -17,17 +17,25 @@ limitations under the License.
 package job
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
 	v1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 )
 
 func TestViewJob(t *testing.T) {
 	response := v1alpha1.Job{}
 	response.Name = "testJob"
+	response.Namespace = "test"
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -63,3 +71,56 @@ func TestViewJob(t *testing.T) {
 	}
 
 }
+
+func TestPrintJobDetails(t *testing.T) {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "testJob", Namespace: "test"},
+		Spec: v1alpha1.JobSpec{
+			Tasks: []v1alpha1.TaskSpec{{Name: "ps"}, {Name: "worker"}},
+		},
+	}
+	pods := []v1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "testJob-worker-0",
+				Annotations: map[string]string{v1alpha1.TaskSpecKey: "worker"},
+			},
+			Spec: v1.PodSpec{NodeName: "node1"},
+			Status: v1.PodStatus{
+				Phase:             v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{{RestartCount: 2}},
+			},
+		},
+	}
+	pg := &kbv1.PodGroup{
+		Status: kbv1.PodGroupStatus{
+			Phase: kbv1.PodGroupPending,
+			Conditions: []kbv1.PodGroupCondition{
+				{
+					Type:    kbv1.PodGroupUnschedulableType,
+					Status:  v1.ConditionTrue,
+					Reason:  kbv1.NotEnoughResourcesReason,
+					Message: "1/2 tasks in gang unschedulable",
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	PrintTaskPods(job, pods, &buf)
+	PrintPodGroupConditions(pg, &buf)
+	PrintEvents(nil, &buf)
+
+	output := buf.String()
+	for _, expect := range []string{
+		"  ps:\n    <none>",
+		"testJob-worker-0   Running       node1                    2",
+		"Unschedulable",
+		"1/2 tasks in gang unschedulable",
+		"Events:\n  <none>",
+	} {
+		if !strings.Contains(output, expect) {
+			t.Errorf("expected %q in output, got %q", expect, output)
+		}
+	}
+}
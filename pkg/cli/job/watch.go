@@ -0,0 +1,238 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/client/clientset/versioned"
+)
+
+const (
+	// clearScreen is the ANSI escape sequence to move the cursor home and clear the screen
+	clearScreen = "\033[H\033[2J"
+
+	// maxRecentEvents is the number of the latest events printed when watching a job
+	maxRecentEvents = 10
+)
+
+type watchFlags struct {
+	commonFlags
+
+	Namespace string
+	JobName   string
+}
+
+var watchJobFlags = &watchFlags{}
+
+// InitWatchFlags init the watch command flags
+func InitWatchFlags(cmd *cobra.Command) {
+	initFlags(cmd, &watchJobFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&watchJobFlags.Namespace, "namespace", "N", "", "the namespace of job, the namespace of kubeconfig context is used if not set")
+	cmd.Flags().StringVarP(&watchJobFlags.JobName, "name", "n", "", "the name of job, all jobs in namespace are watched if not set")
+}
+
+// WatchJob watches the job, or all jobs in namespace, and redraws their status on change
+func WatchJob() error {
+	config, err := buildConfig(&watchJobFlags.commonFlags, &watchJobFlags.Namespace)
+	if err != nil {
+		return err
+	}
+
+	return watchJobs(config, watchJobFlags.Namespace, watchJobFlags.JobName, "", os.Stdout, wait.NeverStop)
+}
+
+// watchJobs watches the jobs matching the label selector and redraws them on change until stopCh
+// is closed; the watches closed by apiserver are re-established by the informers. If name is set,
+// only the job is watched, and its recent events are watched and printed too.
+func watchJobs(config *rest.Config, namespace, name, selector string, writer io.Writer, stopCh <-chan struct{}) error {
+	jobClient := versioned.NewForConfigOrDie(config)
+	kubeClient := kubernetes.NewForConfigOrDie(config)
+
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { notify() },
+		UpdateFunc: func(oldObj, newObj interface{}) { notify() },
+		DeleteFunc: func(obj interface{}) { notify() },
+	}
+
+	setOptions := func(options *metav1.ListOptions) {
+		options.LabelSelector = selector
+		if name != "" {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}
+	}
+	jobStore, jobController := cache.NewInformer(&cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			setOptions(&options)
+			return jobClient.BatchV1alpha1().Jobs(namespace).List(options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			setOptions(&options)
+			return jobClient.BatchV1alpha1().Jobs(namespace).Watch(options)
+		},
+	}, &v1alpha1.Job{}, 0, handler)
+	go jobController.Run(stopCh)
+
+	var eventStore cache.Store
+	if name != "" {
+		// The events are not related to the changes of job, e.g. the failures of scheduling.
+		selector := fields.Set{
+			"involvedObject.kind": "Job",
+			"involvedObject.name": name,
+		}.AsSelector()
+		var eventController cache.Controller
+		eventStore, eventController = cache.NewInformer(
+			cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "events", namespace, selector),
+			&v1.Event{}, 0, handler)
+		go eventController.Run(stopCh)
+	}
+
+	for {
+		select {
+		case <-stopCh:
+			return nil
+		case <-changed:
+		}
+
+		if _, err := fmt.Fprint(writer, clearScreen); err != nil {
+			return err
+		}
+		if name == "" {
+			jobs := map[string]*v1alpha1.Job{}
+			for _, obj := range jobStore.List() {
+				job := obj.(*v1alpha1.Job)
+				jobs[job.Namespace+"/"+job.Name] = job
+			}
+			printWatchedJobs(jobs, writer)
+			continue
+		}
+
+		obj, found, _ := jobStore.GetByKey(namespace + "/" + name)
+		if !found {
+			fmt.Fprintf(writer, "Job %s/%s is not found\n", namespace, name)
+			continue
+		}
+		job := obj.(*v1alpha1.Job)
+
+		var events []v1.Event
+		for _, obj := range eventStore.List() {
+			events = append(events, *obj.(*v1.Event))
+		}
+		if err := printJobDetails(config, job, recentJobEvents(events, job), writer); err != nil {
+			return err
+		}
+	}
+}
+
+func printWatchedJobs(jobs map[string]*v1alpha1.Job, writer io.Writer) {
+	if len(jobs) == 0 {
+		fmt.Fprintf(writer, "No resources found\n")
+		return
+	}
+
+	jobList := &v1alpha1.JobList{}
+	for _, job := range jobs {
+		jobList.Items = append(jobList.Items, *job)
+	}
+	now := time.Now()
+	jobList = filterJobs(jobList, now)
+	sortJobs(jobList, listJobFlags.SortBy, now)
+	PrintJobs(jobList, writer)
+}
+
+// getJobEvents returns the latest events of the job, sorted by time
+func getJobEvents(kubeClient kubernetes.Interface, job *v1alpha1.Job) ([]v1.Event, error) {
+	selector := fields.Set{
+		"involvedObject.kind": "Job",
+		"involvedObject.name": job.Name,
+		"involvedObject.uid":  string(job.UID),
+	}.AsSelector().String()
+	eventList, err := kubeClient.CoreV1().Events(job.Namespace).List(metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	return recentJobEvents(eventList.Items, job), nil
+}
+
+// recentJobEvents returns the latest events of the job sorted by time, the events of
+// the deleted jobs of the same name are ignored.
+func recentJobEvents(events []v1.Event, job *v1alpha1.Job) []v1.Event {
+	var result []v1.Event
+	for _, event := range events {
+		if event.InvolvedObject.UID == job.UID {
+			result = append(result, event)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastTimestamp.Before(&result[j].LastTimestamp)
+	})
+	if len(result) > maxRecentEvents {
+		result = result[len(result)-maxRecentEvents:]
+	}
+	return result
+}
+
+// PrintEvents prints the events of job
+func PrintEvents(events []v1.Event, writer io.Writer) {
+	_, err := fmt.Fprintf(writer, "Events:\n")
+	if err != nil {
+		fmt.Printf("Failed to print events: %s.\n", err)
+	}
+	if len(events) == 0 {
+		fmt.Fprintf(writer, "  <none>\n")
+		return
+	}
+
+	_, err = fmt.Fprintf(writer, "  %-10s%-25s%-25s%s\n", "Type", "Reason", "Last Seen", "Message")
+	if err != nil {
+		fmt.Printf("Failed to print events: %s.\n", err)
+	}
+	for _, event := range events {
+		_, err = fmt.Fprintf(writer, "  %-10s%-25s%-25s%s\n", event.Type, event.Reason,
+			event.LastTimestamp.Format("2006-01-02 15:04:05"), event.Message)
+		if err != nil {
+			fmt.Printf("Failed to print events: %s.\n", err)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+
+	v1alpha1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+// syncBuffer is the buffer written by watchJobs and read by test concurrently
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+// waitClosed keeps the watch open until it is closed by client
+func waitClosed(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	<-r.Context().Done()
+}
+
+func TestWatchJob(t *testing.T) {
+	job := v1alpha1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "testJob",
+			Namespace:       "test",
+			UID:             "job-uid",
+			ResourceVersion: "1",
+		},
+	}
+	pendingJob := job.DeepCopy()
+	pendingJob.Status.State.Phase = v1alpha1.Pending
+	runningJob := job.DeepCopy()
+	runningJob.ResourceVersion = "2"
+	runningJob.Status.State.Phase = v1alpha1.Running
+
+	events := v1.EventList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EventList"},
+	}
+	events.Items = append(events.Items, v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "event", Namespace: "test"},
+		InvolvedObject: v1.ObjectReference{Kind: "Job", Name: job.Name, UID: job.UID},
+		Type:           v1.EventTypeWarning,
+		Reason:         "PodGroupError",
+		Message:        "failed to create PodGroup",
+	})
+
+	var (
+		lock        sync.Mutex
+		jobWatches  int
+		lastVersion string
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		watching := r.URL.Query().Get("watch") == "true"
+		if strings.HasSuffix(r.URL.Path, "/events") {
+			if watching {
+				waitClosed(w, r)
+				return
+			}
+			val, err := json.Marshal(events)
+			if err == nil {
+				w.Write(val)
+			}
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/pods") {
+			val, err := json.Marshal(v1.PodList{})
+			if err == nil {
+				w.Write(val)
+			}
+			return
+		}
+		if strings.Contains(r.URL.Path, "/podgroups/") {
+			w.WriteHeader(http.StatusNotFound)
+			val, err := json.Marshal(metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonNotFound, Code: http.StatusNotFound})
+			if err == nil {
+				w.Write(val)
+			}
+			return
+		}
+
+		if !watching {
+			jobList := v1alpha1.JobList{
+				ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+				Items:    []v1alpha1.Job{*pendingJob},
+			}
+			val, err := json.Marshal(jobList)
+			if err == nil {
+				w.Write(val)
+			}
+			return
+		}
+
+		lock.Lock()
+		jobWatches++
+		lastVersion = r.URL.Query().Get("resourceVersion")
+		lock.Unlock()
+
+		// the job is modified once, then the watch is closed by apiserver
+		if r.URL.Query().Get("resourceVersion") != "1" {
+			waitClosed(w, r)
+			return
+		}
+		raw, err := json.Marshal(runningJob)
+		if err != nil {
+			return
+		}
+		val, err := json.Marshal(metav1.WatchEvent{
+			Type:   string(watch.Modified),
+			Object: runtime.RawExtension{Raw: raw},
+		})
+		if err == nil {
+			w.Write(val)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	config, err := buildConfig(&commonFlags{Master: server.URL}, nil)
+	if err != nil {
+		t.Fatalf("failed to build config: %v", err)
+	}
+
+	testCases := []struct {
+		Name          string
+		JobName       string
+		ExpectOutputs []string
+	}{
+		{
+			Name:          "WatchJobs",
+			JobName:       "",
+			ExpectOutputs: []string{string(v1alpha1.Running)},
+		},
+		{
+			Name:          "WatchJob",
+			JobName:       "testJob",
+			ExpectOutputs: []string{string(v1alpha1.Running), "failed to create PodGroup"},
+		},
+	}
+
+	for i, testcase := range testCases {
+		lock.Lock()
+		jobWatches = 0
+		lock.Unlock()
+
+		buf := &syncBuffer{}
+		stopCh := make(chan struct{})
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- watchJobs(config, "test", testcase.JobName, "", buf, stopCh)
+		}()
+
+		// wait until the watch closed by apiserver is re-established from the last resource version
+		err := wait.Poll(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+			lock.Lock()
+			reconnected := jobWatches >= 2 && lastVersion == "2"
+			lock.Unlock()
+			if !reconnected {
+				return false, nil
+			}
+			output := buf.String()
+			for _, expect := range testcase.ExpectOutputs {
+				if !strings.Contains(output, expect) {
+					return false, nil
+				}
+			}
+			return true, nil
+		})
+		close(stopCh)
+		if err != nil {
+			t.Errorf("case %d (%s): expected %q in output after reconnection, got %q", i, testcase.Name, testcase.ExpectOutputs, buf.String())
+		}
+		if err := <-errCh; err != nil {
+			t.Errorf("case %d (%s): expected no error, got %v", i, testcase.Name, err)
+		}
+		if !strings.Contains(buf.String(), clearScreen) {
+			t.Errorf("case %d (%s): expected redraws, got %q", i, testcase.Name, buf.String())
+		}
+	}
+}
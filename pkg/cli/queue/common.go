@@ -17,14 +17,15 @@ limitations under the License.
 package queue
 
 import (
-	"path/filepath"
-
 	"github.com/spf13/cobra"
 )
 
 type commonFlags struct {
 	Master        string
 	Kubeconfig    string
+	Context       string
+	AsUser        string
+	AsGroups      []string
 	SchedulerName string
 }
 
@@ -32,9 +33,10 @@ func initFlags(cmd *cobra.Command, cf *commonFlags) {
 	cmd.Flags().StringVarP(&cf.SchedulerName, "scheduler", "", "kube-batch", "the scheduler for this job")
 	cmd.Flags().StringVarP(&cf.Master, "master", "s", "", "the address of apiserver")
 
-	if home := homeDir(); home != "" {
-		cmd.Flags().StringVarP(&cf.Kubeconfig, "kubeconfig", "", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
-	} else {
-		cmd.Flags().StringVarP(&cf.Kubeconfig, "kubeconfig", "", "", "(optional) absolute path to the kubeconfig file")
-	}
+	cmd.Flags().StringVarP(&cf.Kubeconfig, "kubeconfig", "", "",
+		"(optional) absolute path to the kubeconfig file, KUBECONFIG or ~/.kube/config is used if not set")
+	cmd.Flags().StringVarP(&cf.Context, "context", "", "", "the name of the kubeconfig context to use")
+	cmd.Flags().StringVarP(&cf.AsUser, "as", "", "", "username to impersonate for the operation")
+	cmd.Flags().StringArrayVarP(&cf.AsGroups, "as-group", "", nil,
+		"group to impersonate for the operation, this flag can be repeated to specify multiple groups")
 }
@@ -45,7 +45,7 @@ func InitRunFlags(cmd *cobra.Command) {
 
 // CreateQueue creates queue
 func CreateQueue() error {
-	config, err := buildConfig(createQueueFlags.Master, createQueueFlags.Kubeconfig)
+	config, err := buildConfig(&createQueueFlags.commonFlags)
 	if err != nil {
 		return err
 	}
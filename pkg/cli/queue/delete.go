@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
+
+	"volcano.sh/volcano/pkg/apis/helpers"
+	vkver "volcano.sh/volcano/pkg/client/clientset/versioned"
+)
+
+type deleteFlags struct {
+	commonFlags
+
+	Name    string
+	Force   bool
+	Timeout time.Duration
+}
+
+// podGroupPollInterval is the interval to check whether the PodGroups deleted by --force are gone
+var podGroupPollInterval = time.Second
+
+var deleteQueueFlags = &deleteFlags{}
+
+// InitDeleteFlags is used to init all flags during queue deleting
+func InitDeleteFlags(cmd *cobra.Command) {
+	initFlags(cmd, &deleteQueueFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&deleteQueueFlags.Name, "name", "n", "", "the name of queue")
+	cmd.Flags().BoolVarP(&deleteQueueFlags.Force, "force", "f", false,
+		"delete the queue even if it is still used; the Jobs and PodGroups in the queue are also deleted")
+	cmd.Flags().DurationVarP(&deleteQueueFlags.Timeout, "timeout", "", time.Minute,
+		"how long to wait for the PodGroups in the queue to be deleted by --force before deleting the queue")
+}
+
+// DeleteQueue deletes the queue
+func DeleteQueue() error {
+	config, err := buildConfig(&deleteQueueFlags.commonFlags)
+	if err != nil {
+		return err
+	}
+
+	if deleteQueueFlags.Name == "" {
+		err := fmt.Errorf("name is mandatory to delete the particular queue")
+		return err
+	}
+
+	queueClient := versioned.NewForConfigOrDie(config)
+	pgs, err := listQueuePodGroups(queueClient, deleteQueueFlags.Name)
+	if err != nil {
+		return err
+	}
+
+	if len(pgs) != 0 {
+		if !deleteQueueFlags.Force {
+			return fmt.Errorf("queue %s is still used by %d PodGroups, use --force to delete them together",
+				deleteQueueFlags.Name, len(pgs))
+		}
+
+		if err := deletePodGroups(config, queueClient, pgs); err != nil {
+			return err
+		}
+		// The queue can not be deleted until its PodGroups are being deleted, which are deleted by
+		// the garbage collector after their Jobs.
+		if err := waitPodGroupsDeleting(queueClient, deleteQueueFlags.Name, deleteQueueFlags.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if err := queueClient.SchedulingV1alpha1().Queues().Delete(deleteQueueFlags.Name, &metav1.DeleteOptions{}); err != nil {
+		return err
+	}
+	fmt.Printf("delete queue %v successfully\n", deleteQueueFlags.Name)
+
+	return nil
+}
+
+// listQueuePodGroups returns all PodGroups in the queue
+func listQueuePodGroups(queueClient versioned.Interface, name string) ([]kbv1.PodGroup, error) {
+	pgList, err := queueClient.SchedulingV1alpha1().PodGroups(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var pgs []kbv1.PodGroup
+	for _, pg := range pgList.Items {
+		if pg.Spec.Queue == name {
+			pgs = append(pgs, pg)
+		}
+	}
+
+	return pgs, nil
+}
+
+// waitPodGroupsDeleting waits until all PodGroups in the queue are gone or being deleted
+func waitPodGroupsDeleting(queueClient versioned.Interface, name string, timeout time.Duration) error {
+	var remaining int
+	err := wait.PollImmediate(podGroupPollInterval, timeout, func() (bool, error) {
+		pgs, err := listQueuePodGroups(queueClient, name)
+		if err != nil {
+			return false, err
+		}
+
+		remaining = 0
+		for _, pg := range pgs {
+			if pg.DeletionTimestamp == nil {
+				remaining++
+			}
+		}
+		return remaining == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for %d PodGroups in queue %s deleted", remaining, name)
+	}
+	return err
+}
+
+// deletePodGroups deletes the PodGroups; if the PodGroup is controlled by a Job, the Job is deleted instead,
+// otherwise the Job controller will create the PodGroup again.
+func deletePodGroups(config *rest.Config, queueClient versioned.Interface, pgs []kbv1.PodGroup) error {
+	jobClient := vkver.NewForConfigOrDie(config)
+	for _, pg := range pgs {
+		if ref := metav1.GetControllerOf(&pg); ref != nil && ref.Kind == helpers.JobKind.Kind {
+			if err := jobClient.BatchV1alpha1().Jobs(pg.Namespace).Delete(ref.Name, &metav1.DeleteOptions{}); err != nil {
+				return err
+			}
+			fmt.Printf("delete job %s/%s in queue %s\n", pg.Namespace, ref.Name, pg.Spec.Queue)
+			continue
+		}
+
+		if err := queueClient.SchedulingV1alpha1().PodGroups(pg.Namespace).Delete(pg.Name, &metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		fmt.Printf("delete podgroup %s/%s in queue %s\n", pg.Namespace, pg.Name, pg.Spec.Queue)
+	}
+
+	return nil
+}
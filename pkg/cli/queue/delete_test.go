@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+)
+
+func TestDeleteQueue(t *testing.T) {
+	newPodGroupList := func(deleting bool) kbv1.PodGroupList {
+		pg := kbv1.PodGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "pg1", Namespace: "test"},
+			Spec:       kbv1.PodGroupSpec{Queue: "q1"},
+		}
+		if deleting {
+			now := metav1.Now()
+			pg.DeletionTimestamp = &now
+		}
+		return kbv1.PodGroupList{Items: []kbv1.PodGroup{pg}}
+	}
+
+	// The PodGroup is being deleted after deleted if gone is set, otherwise it is kept.
+	var gone, deleted bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var response interface{} = metav1.Status{Status: metav1.StatusSuccess}
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/podgroups"):
+			response = newPodGroupList(gone && deleted)
+		case r.Method == http.MethodDelete && strings.Contains(r.URL.Path, "/podgroups/"):
+			deleted = true
+		}
+		val, err := json.Marshal(response)
+		if err == nil {
+			w.Write(val)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	deleteQueueFlags.Master = server.URL
+	deleteQueueFlags.Timeout = 50 * time.Millisecond
+	podGroupPollInterval = 10 * time.Millisecond
+	defer func() {
+		podGroupPollInterval = time.Second
+	}()
+
+	testCases := []struct {
+		Name        string
+		QueueName   string
+		Force       bool
+		Gone        bool
+		ExpectError bool
+	}{
+		{
+			Name:        "DeleteUnusedQueue",
+			QueueName:   "q2",
+			ExpectError: false,
+		},
+		{
+			Name:        "DeleteUsedQueue",
+			QueueName:   "q1",
+			ExpectError: true,
+		},
+		{
+			Name:        "ForceDeleteUsedQueue",
+			QueueName:   "q1",
+			Force:       true,
+			Gone:        true,
+			ExpectError: false,
+		},
+		{
+			Name:        "ForceDeleteUsedQueueTimeout",
+			QueueName:   "q1",
+			Force:       true,
+			Gone:        false,
+			ExpectError: true,
+		},
+	}
+
+	for i, testcase := range testCases {
+		deleteQueueFlags.Name = testcase.QueueName
+		deleteQueueFlags.Force = testcase.Force
+		gone, deleted = testcase.Gone, false
+
+		err := DeleteQueue()
+		if (err != nil) != testcase.ExpectError {
+			t.Errorf("case %d (%s): expected error: %v, got %v ", i, testcase.Name, testcase.ExpectError, err)
+		}
+	}
+	deleteQueueFlags.Force = false
+}
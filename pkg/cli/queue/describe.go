@@ -0,0 +1,146 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
+	vkver "volcano.sh/volcano/pkg/client/clientset/versioned"
+)
+
+const (
+	// Capability of the queue
+	Capability string = "Capability"
+
+	// Phase of the job or PodGroup
+	Phase string = "Phase"
+
+	// MinMember of the PodGroup
+	MinMember string = "MinMember"
+
+	// Requests of the job or PodGroup
+	Requests string = "Requests"
+)
+
+type describeFlags struct {
+	commonFlags
+
+	Name string
+}
+
+var describeQueueFlags = &describeFlags{}
+
+// InitDescribeFlags is used to init all flags during queue describing
+func InitDescribeFlags(cmd *cobra.Command) {
+	initFlags(cmd, &describeQueueFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&describeQueueFlags.Name, "name", "n", "", "the name of queue")
+}
+
+// DescribeQueue gives full details of the queue, including the jobs and PodGroups in it
+func DescribeQueue() error {
+	config, err := buildConfig(&describeQueueFlags.commonFlags)
+	if err != nil {
+		return err
+	}
+
+	if describeQueueFlags.Name == "" {
+		err := fmt.Errorf("name is mandatory to describe the particular queue")
+		return err
+	}
+
+	queueClient := versioned.NewForConfigOrDie(config)
+	queue, err := queueClient.SchedulingV1alpha1().Queues().Get(describeQueueFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	pgs, err := listQueuePodGroups(queueClient, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	jobClient := vkver.NewForConfigOrDie(config)
+	jobList, err := jobClient.BatchV1alpha1().Jobs(metav1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var jobs []vkv1.Job
+	for _, job := range jobList.Items {
+		if job.Spec.Queue == queue.Name {
+			jobs = append(jobs, job)
+		}
+	}
+
+	PrintQueueDetail(queue, jobs, pgs, os.Stdout)
+
+	return nil
+}
+
+// PrintQueueDetail prints the queue details, together with its jobs and PodGroups
+func PrintQueueDetail(queue *kbv1.Queue, jobs []vkv1.Job, pgs []kbv1.PodGroup, writer io.Writer) {
+	lines := []string{
+		fmt.Sprintf("%s:\t\t%s", Name, queue.Name),
+		fmt.Sprintf("%s:\t\t%d", Weight, queue.Spec.Weight),
+		fmt.Sprintf("%s:\t%s", Capability, formatResourceList(queue.Spec.Capability)),
+		"Status",
+		fmt.Sprintf("  %s:\t%d", Pending, queue.Status.Pending),
+		fmt.Sprintf("  %s:\t%d", Running, queue.Status.Running),
+		fmt.Sprintf("  %s:\t%d", Unknown, queue.Status.Unknown),
+		fmt.Sprintf("Jobs:\t\t%d", len(jobs)),
+	}
+	for _, job := range jobs {
+		lines = append(lines,
+			fmt.Sprintf("  %s/%s", job.Namespace, job.Name),
+			fmt.Sprintf("    %s:\t%s", Phase, job.Status.State.Phase),
+			fmt.Sprintf("    %s:\t%s", Requests, formatResourceList(helpers.GetJobRequests(&job))),
+		)
+	}
+
+	lines = append(lines, fmt.Sprintf("PodGroups:\t%d", len(pgs)))
+	for _, pg := range pgs {
+		var requests string
+		if pg.Spec.MinResources != nil {
+			requests = formatResourceList(*pg.Spec.MinResources)
+		} else {
+			requests = formatResourceList(nil)
+		}
+		lines = append(lines,
+			fmt.Sprintf("  %s/%s", pg.Namespace, pg.Name),
+			fmt.Sprintf("    %s:\t%s", Phase, pg.Status.Phase),
+			fmt.Sprintf("    %s:\t%d", MinMember, pg.Spec.MinMember),
+			fmt.Sprintf("    %s:\t%s", Requests, requests),
+		)
+	}
+
+	_, err := fmt.Fprint(writer, strings.Join(lines, "\n"), "\n")
+	if err != nil {
+		fmt.Printf("Failed to print queue command result: %s.\n", err)
+	}
+}
@@ -26,12 +26,15 @@ import (
 	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 	"github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/cli/util"
 )
 
 type getFlags struct {
 	commonFlags
 
-	Name string
+	Name   string
+	Output string
 }
 
 var getQueueFlags = &getFlags{}
@@ -41,12 +44,13 @@ func InitGetFlags(cmd *cobra.Command) {
 	initFlags(cmd, &getQueueFlags.commonFlags)
 
 	cmd.Flags().StringVarP(&getQueueFlags.Name, "name", "n", "", "the name of queue")
+	cmd.Flags().StringVarP(&getQueueFlags.Output, "output", "o", "", util.OutputUsage)
 
 }
 
 // GetQueue gets a queue
 func GetQueue() error {
-	config, err := buildConfig(getQueueFlags.Master, getQueueFlags.Kubeconfig)
+	config, err := buildConfig(&getQueueFlags.commonFlags)
 	if err != nil {
 		return err
 	}
@@ -56,12 +60,20 @@ func GetQueue() error {
 		return err
 	}
 
+	if err := util.ValidateOutput(getQueueFlags.Output); err != nil {
+		return err
+	}
+
 	queueClient := versioned.NewForConfigOrDie(config)
 	queue, err := queueClient.SchedulingV1alpha1().Queues().Get(getQueueFlags.Name, metav1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
+	if !util.IsTableOutput(getQueueFlags.Output) {
+		return util.PrintObject(getQueueFlags.Output, QueueResource, queue, os.Stdout)
+	}
+
 	PrintQueue(queue, os.Stdout)
 
 	return nil
@@ -69,14 +81,7 @@ func GetQueue() error {
 
 // PrintQueue prints queue information
 func PrintQueue(queue *v1alpha1.Queue, writer io.Writer) {
-	_, err := fmt.Fprintf(writer, "%-25s%-8s%-8s%-8s%-8s\n",
-		Name, Weight, Pending, Running, Unknown)
-	if err != nil {
-		fmt.Printf("Failed to print queue command result: %s.\n", err)
-	}
-	_, err = fmt.Fprintf(writer, "%-25s%-8d%-8d%-8d%-8d\n",
-		queue.Name, queue.Spec.Weight, queue.Status.Pending, queue.Status.Running, queue.Status.Unknown)
-	if err != nil {
-		fmt.Printf("Failed to print queue command result: %s.\n", err)
-	}
+	wide := getQueueFlags.Output == util.OutputWide
+	printQueueHeader(writer, wide)
+	printQueueRow(queue, writer, wide)
 }
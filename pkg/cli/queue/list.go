@@ -26,10 +26,14 @@ import (
 	"github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 	"github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/cli/util"
 )
 
 type listFlags struct {
 	commonFlags
+
+	Output string
 }
 
 const (
@@ -49,26 +53,39 @@ const (
 	Unknown string = "Unknown"
 )
 
+// QueueResource is the resource name of queue, used in the name output
+const QueueResource = "queue." + v1alpha1.GroupName
+
 var listQueueFlags = &listFlags{}
 
 // InitListFlags inits all flags
 func InitListFlags(cmd *cobra.Command) {
 	initFlags(cmd, &listQueueFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&listQueueFlags.Output, "output", "o", "", util.OutputUsage)
 }
 
 // ListQueue lists all the queue
 func ListQueue() error {
-	config, err := buildConfig(listQueueFlags.Master, listQueueFlags.Kubeconfig)
+	config, err := buildConfig(&listQueueFlags.commonFlags)
 	if err != nil {
 		return err
 	}
 
+	if err := util.ValidateOutput(listQueueFlags.Output); err != nil {
+		return err
+	}
+
 	jobClient := versioned.NewForConfigOrDie(config)
 	queues, err := jobClient.SchedulingV1alpha1().Queues().List(metav1.ListOptions{})
 	if err != nil {
 		return err
 	}
 
+	if !util.IsTableOutput(listQueueFlags.Output) {
+		return util.PrintObject(listQueueFlags.Output, QueueResource, queues, os.Stdout)
+	}
+
 	if len(queues.Items) == 0 {
 		fmt.Printf("No resources found\n")
 		return nil
@@ -80,17 +97,33 @@ func ListQueue() error {
 
 // PrintQueues prints queue information
 func PrintQueues(queues *v1alpha1.QueueList, writer io.Writer) {
-	_, err := fmt.Fprintf(writer, "%-25s%-8s%-8s%-8s%-8s\n",
-		Name, Weight, Pending, Running, Unknown)
+	wide := listQueueFlags.Output == util.OutputWide
+	printQueueHeader(writer, wide)
+	for i := range queues.Items {
+		printQueueRow(&queues.Items[i], writer, wide)
+	}
+
+}
+
+func printQueueHeader(writer io.Writer, wide bool) {
+	header := fmt.Sprintf("%-25s%-8s%-8s%-8s%-8s", Name, Weight, Pending, Running, Unknown)
+	if wide {
+		header += Capability
+	}
+	_, err := fmt.Fprintln(writer, header)
 	if err != nil {
 		fmt.Printf("Failed to print queue command result: %s.\n", err)
 	}
-	for _, queue := range queues.Items {
-		_, err = fmt.Fprintf(writer, "%-25s%-8d%-8d%-8d%-8d\n",
-			queue.Name, queue.Spec.Weight, queue.Status.Pending, queue.Status.Running, queue.Status.Unknown)
-		if err != nil {
-			fmt.Printf("Failed to print queue command result: %s.\n", err)
-		}
-	}
+}
 
+func printQueueRow(queue *v1alpha1.Queue, writer io.Writer, wide bool) {
+	row := fmt.Sprintf("%-25s%-8d%-8d%-8d%-8d",
+		queue.Name, queue.Spec.Weight, queue.Status.Pending, queue.Status.Running, queue.Status.Unknown)
+	if wide {
+		row += formatResourceList(queue.Spec.Capability)
+	}
+	_, err := fmt.Fprintln(writer, row)
+	if err != nil {
+		fmt.Printf("Failed to print queue command result: %s.\n", err)
+	}
 }
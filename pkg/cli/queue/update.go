@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
+
+	"volcano.sh/volcano/pkg/cli/util"
+)
+
+type updateFlags struct {
+	commonFlags
+
+	Name       string
+	Weight     int32
+	Capability string
+}
+
+var updateQueueFlags = &updateFlags{}
+
+// InitUpdateFlags is used to init all flags during queue updating
+func InitUpdateFlags(cmd *cobra.Command) {
+	initFlags(cmd, &updateQueueFlags.commonFlags)
+
+	cmd.Flags().StringVarP(&updateQueueFlags.Name, "name", "n", "", "the name of queue")
+	cmd.Flags().Int32VarP(&updateQueueFlags.Weight, "weight", "w", 0, "the weight of the queue, unchanged if not set")
+	cmd.Flags().StringVarP(&updateQueueFlags.Capability, "capability", "c", "", "the capability of the queue, e.g. cpu=10,memory=10Gi; unchanged if not set")
+}
+
+// UpdateQueue updates the weight or capability of queue
+func UpdateQueue() error {
+	config, err := buildConfig(&updateQueueFlags.commonFlags)
+	if err != nil {
+		return err
+	}
+
+	if updateQueueFlags.Name == "" {
+		err := fmt.Errorf("name is mandatory to update the particular queue")
+		return err
+	}
+
+	if updateQueueFlags.Weight < 0 {
+		err := fmt.Errorf("weight of queue can not be negative")
+		return err
+	}
+
+	capability, err := util.PopulateResourceListV1(updateQueueFlags.Capability)
+	if err != nil {
+		return err
+	}
+
+	queueClient := versioned.NewForConfigOrDie(config)
+	queue, err := queueClient.SchedulingV1alpha1().Queues().Get(updateQueueFlags.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if updateQueueFlags.Weight != 0 {
+		queue.Spec.Weight = updateQueueFlags.Weight
+	}
+	if capability != nil {
+		queue.Spec.Capability = capability
+	}
+
+	if _, err := queueClient.SchedulingV1alpha1().Queues().Update(queue); err != nil {
+		return err
+	}
+	fmt.Printf("update queue %v successfully\n", updateQueueFlags.Name)
+
+	return nil
+}
@@ -17,21 +17,33 @@ limitations under the License.
 package queue
 
 import (
-	"os"
+	"fmt"
+	"sort"
+	"strings"
 
+	"k8s.io/api/core/v1"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	// Initialize client auth plugin.
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+
+	"volcano.sh/volcano/pkg/cli/util"
 )
 
-func homeDir() string {
-	if h := os.Getenv("HOME"); h != "" {
-		return h
-	}
-	return os.Getenv("USERPROFILE") // windows
+func buildConfig(cf *commonFlags) (*rest.Config, error) {
+	return util.ClientConfig(cf.Master, cf.Kubeconfig, cf.Context, cf.AsUser, cf.AsGroups).ClientConfig()
 }
 
-func buildConfig(master, kubeconfig string) (*rest.Config, error) {
-	return clientcmd.BuildConfigFromFlags(master, kubeconfig)
+// formatResourceList returns ResourceList in the form of <resourceName1>=<value1>,<resourceName1>=<value2>
+func formatResourceList(list v1.ResourceList) string {
+	if len(list) == 0 {
+		return "<none>"
+	}
+
+	statements := make([]string, 0, len(list))
+	for name, quantity := range list {
+		statements = append(statements, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(statements)
+
+	return strings.Join(statements, ",")
 }
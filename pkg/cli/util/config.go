@@ -0,0 +1,37 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ClientConfig returns the client config loaded like kubectl: the kubeconfig is the file set by
+// --kubeconfig, or merged from KUBECONFIG and ~/.kube/config; the apiserver address, context and
+// impersonation set by flags override the ones in kubeconfig.
+func ClientConfig(master, kubeconfig, context, asUser string, asGroups []string) clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.ClusterInfo.Server = master
+	overrides.CurrentContext = context
+	overrides.AuthInfo.Impersonate = asUser
+	overrides.AuthInfo.ImpersonateGroups = asGroups
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+}
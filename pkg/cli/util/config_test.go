@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster1
+  cluster:
+    server: https://cluster1:6443
+- name: cluster2
+  cluster:
+    server: https://cluster2:6443
+users:
+- name: user1
+  user:
+    token: token1
+contexts:
+- name: context1
+  context:
+    cluster: cluster1
+    user: user1
+    namespace: team1
+- name: context2
+  context:
+    cluster: cluster2
+    user: user1
+current-context: context1
+`
+
+func TestClientConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vkctl-config")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(fileName, []byte(kubeconfig), 0644); err != nil {
+		t.Fatalf("failed to write kubeconfig: %v", err)
+	}
+
+	testCases := []struct {
+		Name            string
+		Master          string
+		Context         string
+		AsUser          string
+		AsGroups        []string
+		ExpectHost      string
+		ExpectNamespace string
+	}{
+		{
+			Name:            "CurrentContext",
+			ExpectHost:      "https://cluster1:6443",
+			ExpectNamespace: "team1",
+		},
+		{
+			Name:            "Context",
+			Context:         "context2",
+			ExpectHost:      "https://cluster2:6443",
+			ExpectNamespace: "default",
+		},
+		{
+			Name:            "Master",
+			Master:          "https://master:6443",
+			ExpectHost:      "https://master:6443",
+			ExpectNamespace: "team1",
+		},
+		{
+			Name:            "Impersonate",
+			AsUser:          "user2",
+			AsGroups:        []string{"group1", "group2"},
+			ExpectHost:      "https://cluster1:6443",
+			ExpectNamespace: "team1",
+		},
+	}
+
+	for i, testcase := range testCases {
+		clientConfig := ClientConfig(testcase.Master, fileName, testcase.Context, testcase.AsUser, testcase.AsGroups)
+
+		config, err := clientConfig.ClientConfig()
+		if err != nil {
+			t.Errorf("case %d (%s): expected no error, got %v", i, testcase.Name, err)
+			continue
+		}
+		if config.Host != testcase.ExpectHost {
+			t.Errorf("case %d (%s): expected host %s, got %s", i, testcase.Name, testcase.ExpectHost, config.Host)
+		}
+		if config.Impersonate.UserName != testcase.AsUser || len(config.Impersonate.Groups) != len(testcase.AsGroups) {
+			t.Errorf("case %d (%s): expected impersonate %s %v, got %v", i, testcase.Name,
+				testcase.AsUser, testcase.AsGroups, config.Impersonate)
+		}
+
+		namespace, _, err := clientConfig.Namespace()
+		if err != nil {
+			t.Errorf("case %d (%s): expected no error, got %v", i, testcase.Name, err)
+		}
+		if namespace != testcase.ExpectNamespace {
+			t.Errorf("case %d (%s): expected namespace %s, got %s", i, testcase.Name, testcase.ExpectNamespace, namespace)
+		}
+	}
+}
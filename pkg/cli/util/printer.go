@@ -0,0 +1,255 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+
+	kbscheme "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned/scheme"
+
+	vkscheme "volcano.sh/volcano/pkg/client/clientset/versioned/scheme"
+)
+
+const (
+	// OutputWide prints the table with additional columns
+	OutputWide = "wide"
+	// OutputJSON prints the object in JSON
+	OutputJSON = "json"
+	// OutputYAML prints the object in YAML
+	OutputYAML = "yaml"
+	// OutputName prints the resource and name of the object
+	OutputName = "name"
+	// OutputJSONPath prints the fields of object by the JSONPath template, e.g. jsonpath={.metadata.name}
+	OutputJSONPath = "jsonpath"
+	// OutputCustomColumns prints the table with the columns, e.g. custom-columns=NAME:.metadata.name
+	OutputCustomColumns = "custom-columns"
+
+	// OutputUsage is the usage of the output flag
+	OutputUsage = "output format, one of: json|yaml|wide|name|custom-columns=<header>:<jsonpath>,...|jsonpath=<template>"
+)
+
+// printScheme is used to find the kinds of printed objects
+var printScheme = runtime.NewScheme()
+
+func init() {
+	vkscheme.AddToScheme(printScheme)
+	kbscheme.AddToScheme(printScheme)
+}
+
+// ValidateOutput checks whether the output format is supported
+func ValidateOutput(output string) error {
+	format, template := splitOutput(output)
+	switch format {
+	case "", OutputWide, OutputJSON, OutputYAML, OutputName:
+		if template != "" {
+			return fmt.Errorf("output format %s does not accept a template", format)
+		}
+		return nil
+	case OutputJSONPath, OutputCustomColumns:
+		if template == "" {
+			return fmt.Errorf("output format %s requires a template, e.g. %s=<template>", format, format)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q, %s", output, OutputUsage)
+	}
+}
+
+// IsTableOutput returns whether the output is the default table format, which is printed by the commands themselves
+func IsTableOutput(output string) bool {
+	return output == "" || output == OutputWide
+}
+
+// PrintObject prints the object, or all items if it is a list, by the output format;
+// the resource, e.g. job.batch.volcano.sh, is used as the prefix of names.
+func PrintObject(output, resource string, obj runtime.Object, writer io.Writer) error {
+	// The objects got by typed clients have no apiVersion and kind, set them as kubectl does.
+	obj = obj.DeepCopyObject()
+	if err := setKinds(obj); err != nil {
+		return err
+	}
+
+	format, template := splitOutput(output)
+	switch format {
+	case OutputJSON:
+		data, err := json.MarshalIndent(obj, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(writer, string(data))
+		return err
+	case OutputYAML:
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(writer, string(data))
+		return err
+	case OutputName:
+		return printNames(resource, obj, writer)
+	case OutputJSONPath:
+		return printJSONPath(template, obj, writer)
+	case OutputCustomColumns:
+		return printCustomColumns(template, obj, writer)
+	default:
+		return fmt.Errorf("unsupported output format %q, %s", output, OutputUsage)
+	}
+}
+
+// setKinds sets the apiVersion and kind of the object, and of all items if it is a list
+func setKinds(obj runtime.Object) error {
+	objs := []runtime.Object{obj}
+	if meta.IsListType(obj) {
+		list, err := meta.ExtractList(obj)
+		if err != nil {
+			return err
+		}
+		objs = append(objs, list...)
+	}
+
+	for _, o := range objs {
+		gvks, _, err := printScheme.ObjectKinds(o)
+		if err != nil {
+			return err
+		}
+		o.GetObjectKind().SetGroupVersionKind(gvks[0])
+	}
+	return nil
+}
+
+func splitOutput(output string) (string, string) {
+	parts := strings.SplitN(output, "=", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// items returns the items of list, or the object itself
+func items(obj runtime.Object) ([]runtime.Object, error) {
+	if !meta.IsListType(obj) {
+		return []runtime.Object{obj}, nil
+	}
+	return meta.ExtractList(obj)
+}
+
+// toJSONData converts the object to the generic data used by JSONPath, so the fields are referred by their JSON names
+func toJSONData(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func printNames(resource string, obj runtime.Object, writer io.Writer) error {
+	objs, err := items(obj)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range objs {
+		accessor, err := meta.Accessor(o)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(writer, "%s/%s\n", resource, accessor.GetName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printJSONPath(template string, obj runtime.Object, writer io.Writer) error {
+	parser := jsonpath.New("output")
+	if err := parser.Parse(template); err != nil {
+		return fmt.Errorf("invalid jsonpath template %q: %v", template, err)
+	}
+
+	data, err := toJSONData(obj)
+	if err != nil {
+		return err
+	}
+	return parser.Execute(writer, data)
+}
+
+func printCustomColumns(spec string, obj runtime.Object, writer io.Writer) error {
+	var headers []string
+	var parsers []*jsonpath.JSONPath
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("invalid custom column %q, expected <header>:<jsonpath>", column)
+		}
+
+		path := parts[1]
+		if !strings.HasPrefix(path, "{") {
+			path = "{" + path + "}"
+		}
+		parser := jsonpath.New(parts[0]).AllowMissingKeys(true)
+		if err := parser.Parse(path); err != nil {
+			return fmt.Errorf("invalid jsonpath of custom column %q: %v", column, err)
+		}
+
+		headers = append(headers, parts[0])
+		parsers = append(parsers, parser)
+	}
+
+	objs, err := items(obj)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(writer, 5, 8, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, o := range objs {
+		data, err := toJSONData(o)
+		if err != nil {
+			return err
+		}
+
+		var values []string
+		for _, parser := range parsers {
+			var buf bytes.Buffer
+			if err := parser.Execute(&buf, data); err != nil {
+				return err
+			}
+			value := buf.String()
+			if value == "" {
+				value = "<none>"
+			}
+			values = append(values, value)
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestValidateOutput(t *testing.T) {
+	testCases := []struct {
+		Output      string
+		ExpectError bool
+	}{
+		{Output: "", ExpectError: false},
+		{Output: "wide", ExpectError: false},
+		{Output: "json", ExpectError: false},
+		{Output: "yaml", ExpectError: false},
+		{Output: "name", ExpectError: false},
+		{Output: "jsonpath={.metadata.name}", ExpectError: false},
+		{Output: "custom-columns=NAME:.metadata.name", ExpectError: false},
+		{Output: "jsonpath", ExpectError: true},
+		{Output: "json=x", ExpectError: true},
+		{Output: "table", ExpectError: true},
+	}
+
+	for i, testcase := range testCases {
+		err := ValidateOutput(testcase.Output)
+		if (err != nil) != testcase.ExpectError {
+			t.Errorf("case %d (%s): expected error: %v, got %v", i, testcase.Output, testcase.ExpectError, err)
+		}
+	}
+}
+
+func TestPrintObject(t *testing.T) {
+	jobs := &v1alpha1.JobList{}
+	for _, name := range []string{"job1", "job2"} {
+		job := v1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha1.JobSpec{Queue: "default"},
+		}
+		job.Status.State.Phase = v1alpha1.Running
+		jobs.Items = append(jobs.Items, job)
+	}
+
+	testCases := []struct {
+		Name         string
+		Output       string
+		ExpectOutput string
+		ExpectError  bool
+	}{
+		{
+			Name:         "name",
+			Output:       "name",
+			ExpectOutput: "job.batch.volcano.sh/job1\njob.batch.volcano.sh/job2\n",
+		},
+		{
+			Name:         "kind",
+			Output:       "jsonpath={.apiVersion} {.kind} {.items[0].apiVersion} {.items[0].kind}",
+			ExpectOutput: "batch.volcano.sh/v1alpha1 JobList batch.volcano.sh/v1alpha1 Job",
+		},
+		{
+			Name:         "jsonpath",
+			Output:       "jsonpath={.items[*].metadata.name}",
+			ExpectOutput: "job1 job2",
+		},
+		{
+			Name:   "custom-columns",
+			Output: "custom-columns=NAME:.metadata.name,PHASE:.status.state.phase,RETRY:.status.retryCount",
+			ExpectOutput: "NAME   PHASE     RETRY\n" +
+				"job1   Running   <none>\n" +
+				"job2   Running   <none>\n",
+		},
+		{
+			Name:        "invalid custom-columns",
+			Output:      "custom-columns=NAME",
+			ExpectError: true,
+		},
+	}
+
+	for i, testcase := range testCases {
+		var buf bytes.Buffer
+		err := PrintObject(testcase.Output, "job.batch.volcano.sh", jobs, &buf)
+		if (err != nil) != testcase.ExpectError {
+			t.Errorf("case %d (%s): expected error: %v, got %v", i, testcase.Name, testcase.ExpectError, err)
+		}
+		if !testcase.ExpectError && buf.String() != testcase.ExpectOutput {
+			t.Errorf("case %d (%s): expected output %q, got %q", i, testcase.Name, testcase.ExpectOutput, buf.String())
+		}
+	}
+	if jobs.Kind != "" || jobs.Items[0].Kind != "" {
+		t.Errorf("expected the printed jobs not changed, got kind %q", jobs.Kind)
+	}
+}
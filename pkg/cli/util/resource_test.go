@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPopulateResourceListV1(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Spec      string
+		Expected  v1.ResourceList
+		ExpectErr bool
+	}{
+		{
+			Name: "empty spec",
+		},
+		{
+			Name: "cpu and memory",
+			Spec: "cpu=2,memory=1Gi",
+			Expected: v1.ResourceList{
+				v1.ResourceCPU:    resource.MustParse("2"),
+				v1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+		{
+			Name:      "invalid syntax",
+			Spec:      "cpu:2",
+			ExpectErr: true,
+		},
+		{
+			Name:      "invalid quantity",
+			Spec:      "cpu=two",
+			ExpectErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		list, err := PopulateResourceListV1(testCase.Spec)
+		if (err != nil) != testCase.ExpectErr {
+			t.Errorf("%s: expected error %v, got %v", testCase.Name, testCase.ExpectErr, err)
+			continue
+		}
+		if len(list) != len(testCase.Expected) {
+			t.Errorf("%s: expected %v, got %v", testCase.Name, testCase.Expected, list)
+			continue
+		}
+		for name, quantity := range testCase.Expected {
+			if got := list[name]; got.Cmp(quantity) != 0 {
+				t.Errorf("%s: expected %s=%s, got %s", testCase.Name, name, quantity.String(), got.String())
+			}
+		}
+	}
+}
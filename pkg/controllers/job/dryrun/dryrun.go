@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"fmt"
+	"sort"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/api/scheduling/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	vkjobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
+	vkplugin "volcano.sh/volcano/pkg/controllers/job/plugins"
+	vkinterface "volcano.sh/volcano/pkg/controllers/job/plugins/interface"
+)
+
+// Result is the resources created by the job controller for a job.
+type Result struct {
+	PodGroup   *kbv1.PodGroup
+	Pods       []*v1.Pod
+	PVCs       []*v1.PersistentVolumeClaim
+	Services   []*v1.Service
+	ConfigMaps []*v1.ConfigMap
+}
+
+// Job returns the resources the job controller would create for a new job without creating them,
+// so that users can preview what a job spec expands to. The job is supposed to be defaulted and
+// validated by admission already. The client is only used to get the priority classes of tasks and
+// the existing claims of volumes; the plugins are executed against a fake clientset, which keeps the
+// objects created by them in memory only.
+func Job(client kubernetes.Interface, job *vkv1.Job) (*Result, error) {
+	job = job.DeepCopy()
+	result := &Result{}
+
+	priorityClasses, err := getPriorityClasses(client, job)
+	if err != nil {
+		return nil, err
+	}
+
+	pluginClients := fake.NewSimpleClientset()
+	if job.Status.ControlledResources == nil {
+		job.Status.ControlledResources = make(map[string]string)
+	}
+	if err := runPlugins(pluginClients, job, func(plugin vkinterface.PluginInterface) error {
+		return plugin.OnJobAdd(job)
+	}); err != nil {
+		return nil, err
+	}
+
+	result.PodGroup = vkjobhelpers.NewPodGroup(job, priorityClasses)
+
+	if _, err := vkjobhelpers.PrepareJobVolumes(job, func(vcName string) (bool, error) {
+		_, err := client.CoreV1().PersistentVolumeClaims(job.Namespace).Get(vcName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}, func(vcName string, volumeClaim *v1.PersistentVolumeClaimSpec) error {
+		result.PVCs = append(result.PVCs, vkjobhelpers.NewPVC(job, vcName, volumeClaim))
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, ts := range job.Spec.Tasks {
+		ts.Template.Name = ts.Name
+		tc := ts.Template.DeepCopy()
+		for i := 0; i < int(ts.Replicas); i++ {
+			pod := vkjobhelpers.CreateJobPod(job, tc, i)
+			if err := runPlugins(pluginClients, job, func(plugin vkinterface.PluginInterface) error {
+				return plugin.OnPodCreate(pod, job)
+			}); err != nil {
+				return nil, err
+			}
+			result.Pods = append(result.Pods, pod)
+		}
+	}
+
+	services, err := pluginClients.CoreV1().Services(job.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range services.Items {
+		result.Services = append(result.Services, &services.Items[i])
+	}
+	sort.Slice(result.Services, func(i, j int) bool {
+		return result.Services[i].Name < result.Services[j].Name
+	})
+
+	configMaps, err := pluginClients.CoreV1().ConfigMaps(job.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range configMaps.Items {
+		result.ConfigMaps = append(result.ConfigMaps, &configMaps.Items[i])
+	}
+	sort.Slice(result.ConfigMaps, func(i, j int) bool {
+		return result.ConfigMaps[i].Name < result.ConfigMaps[j].Name
+	})
+
+	return result, nil
+}
+
+// getPriorityClasses returns the priority classes of the tasks, the missing ones are ignored as
+// the controller does.
+func getPriorityClasses(client kubernetes.Interface, job *vkv1.Job) (map[string]*v1beta1.PriorityClass, error) {
+	priorityClasses := map[string]*v1beta1.PriorityClass{}
+	for _, task := range job.Spec.Tasks {
+		name := task.Template.Spec.PriorityClassName
+		if len(name) == 0 || priorityClasses[name] != nil {
+			continue
+		}
+		pc, err := client.SchedulingV1beta1().PriorityClasses().Get(name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get priority class %s: %v", name, err)
+		}
+		priorityClasses[name] = pc
+	}
+	return priorityClasses, nil
+}
+
+// runPlugins executes the plugins of job in the same way as the controller.
+func runPlugins(client kubernetes.Interface, job *vkv1.Job, execute func(plugin vkinterface.PluginInterface) error) error {
+	pluginClients := vkinterface.PluginClientset{KubeClients: client}
+	for name, args := range job.Spec.Plugins {
+		pb, found := vkplugin.GetPluginBuilder(name)
+		if !found {
+			return fmt.Errorf("failed to get plugin %s", name)
+		}
+		if err := execute(pb(pluginClients, args)); err != nil {
+			return fmt.Errorf("failed to execute plugin %s: %v", name, err)
+		}
+	}
+	return nil
+}
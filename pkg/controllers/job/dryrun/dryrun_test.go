@@ -0,0 +1,159 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/api/scheduling/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestJob(t *testing.T) {
+	namespace := "test"
+	newTask := func(name string, replicas int32, cpu, priorityClassName string) vkv1.TaskSpec {
+		return vkv1.TaskSpec{
+			Name:     name,
+			Replicas: replicas,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					PriorityClassName: priorityClassName,
+					Containers: []v1.Container{
+						{
+							Name:  "fake-name",
+							Image: "busybox:1.24",
+							Resources: v1.ResourceRequirements{
+								Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	job := &vkv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: namespace,
+		},
+		Spec: vkv1.JobSpec{
+			MinAvailable: 2,
+			Queue:        "default",
+			Tasks:        []vkv1.TaskSpec{newTask("ps", 1, "2", ""), newTask("worker", 2, "1", "high")},
+			Plugins: map[string][]string{
+				"svc": {},
+				"env": {},
+			},
+			Volumes: []vkv1.VolumeSpec{
+				{
+					MountPath:   "/data",
+					VolumeClaim: &v1.PersistentVolumeClaimSpec{},
+				},
+				{
+					MountPath: "/tmp",
+				},
+				{
+					MountPath:       "/shared",
+					VolumeClaimName: "existing-claim",
+				},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset(
+		&v1beta1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "high"}, Value: 100},
+		&v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "existing-claim", Namespace: namespace}},
+	)
+	result, err := Job(client, job)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(job.Spec.Volumes[0].VolumeClaimName) != 0 || job.Status.ControlledResources != nil {
+		t.Errorf("expected job not changed by dry run")
+	}
+
+	if result.PodGroup == nil || result.PodGroup.Name != job.Name || result.PodGroup.Spec.MinMember != 2 {
+		t.Errorf("expected PodGroup %s with min member 2, got %v", job.Name, result.PodGroup)
+	} else if cpu := (*result.PodGroup.Spec.MinResources)[v1.ResourceCPU]; cpu.Cmp(resource.MustParse("2")) != 0 {
+		t.Errorf("expected PodGroup min cpu 2 of the workers with higher priority, got %s", cpu.String())
+	}
+
+	expectedPods := []string{"job1-ps-0", "job1-worker-0", "job1-worker-1"}
+	if len(result.Pods) != len(expectedPods) {
+		t.Fatalf("expected %d pods, got %d", len(expectedPods), len(result.Pods))
+	}
+	for i, pod := range result.Pods {
+		if pod.Name != expectedPods[i] {
+			t.Errorf("expected pod %s, got %s", expectedPods[i], pod.Name)
+		}
+		if len(pod.Spec.Containers[0].Env) == 0 {
+			t.Errorf("expected env of pod %s set by plugin", pod.Name)
+		}
+		volumes := map[string]bool{}
+		for _, volume := range pod.Spec.Volumes {
+			switch {
+			case volume.Name == "job1-svc":
+				volumes["svc"] = true
+			case volume.Name == "existing-claim" && volume.PersistentVolumeClaim != nil:
+				volumes["existing"] = true
+			case volume.PersistentVolumeClaim != nil:
+				volumes["pvc"] = true
+			case volume.EmptyDir != nil:
+				volumes["emptyDir"] = true
+			}
+		}
+		for _, name := range []string{"svc", "existing", "pvc", "emptyDir"} {
+			if !volumes[name] {
+				t.Errorf("expected %s volume in pod %s, got %v", name, pod.Name, pod.Spec.Volumes)
+			}
+		}
+	}
+
+	if len(result.PVCs) != 1 || !strings.HasPrefix(result.PVCs[0].Name, "job1-volume-") {
+		t.Errorf("expected 1 generated PVC, got %v", result.PVCs)
+	}
+	if len(result.Services) != 1 || result.Services[0].Name != job.Name {
+		t.Errorf("expected service %s, got %v", job.Name, result.Services)
+	}
+	if len(result.ConfigMaps) != 1 || result.ConfigMaps[0].Name != "job1-svc" {
+		t.Errorf("expected ConfigMap job1-svc, got %v", result.ConfigMaps)
+	}
+}
+
+func TestJobUnknownPlugin(t *testing.T) {
+	job := &vkv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "job1",
+			Namespace: "test",
+		},
+		Spec: vkv1.JobSpec{
+			Plugins: map[string][]string{"unknown": {}},
+		},
+	}
+
+	if _, err := Job(fake.NewSimpleClientset(), job); err == nil {
+		t.Errorf("expected error of unknown plugin")
+	}
+}
@@ -20,8 +20,13 @@ import (
 	"fmt"
 	"k8s.io/api/core/v1"
 	"math/rand"
+	"path"
 	"strings"
 	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 )
 
 const (
@@ -61,3 +66,30 @@ func genRandomStr(l int) string {
 func MakeVolumeClaimName(jobName string) string {
 	return fmt.Sprintf(VolumeClaimFmt, jobName, genRandomStr(12))
 }
+
+// ValidateMountPath returns the errors of the volumes of job mounted at the mount path, which is going
+// to be mounted into all containers by the plugin.
+func ValidateMountPath(job *vkv1.Job, mountPath, plugin string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	msg := fmt.Sprintf("conflicts with the volume mounted by %s plugin", plugin)
+	specPath := field.NewPath("spec")
+
+	for i, volume := range job.Spec.Volumes {
+		if path.Clean(volume.MountPath) == mountPath {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("volumes").Index(i).Child("mountPath"), volume.MountPath, msg))
+		}
+	}
+	for i, task := range job.Spec.Tasks {
+		containersPath := specPath.Child("tasks").Index(i).Child("template", "spec", "containers")
+		for j, container := range task.Template.Spec.Containers {
+			for k, vm := range container.VolumeMounts {
+				if path.Clean(vm.MountPath) == mountPath {
+					allErrs = append(allErrs, field.Invalid(
+						containersPath.Index(j).Child("volumeMounts").Index(k).Child("mountPath"), vm.MountPath, msg))
+				}
+			}
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"fmt"
+	"sort"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/api/scheduling/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	apishelpers "volcano.sh/volcano/pkg/apis/helpers"
+)
+
+// CreateJobPod returns the pod of the task template at index ix, with the volumes of job mounted.
+func CreateJobPod(job *vkv1.Job, template *v1.PodTemplateSpec, ix int) *v1.Pod {
+	templateCopy := template.DeepCopy()
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MakePodName(job.Name, template.Name, ix),
+			Namespace: job.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, apishelpers.JobKind),
+			},
+			Labels:      templateCopy.Labels,
+			Annotations: templateCopy.Annotations,
+		},
+		Spec: templateCopy.Spec,
+	}
+
+	// If no scheduler name in Pod, use scheduler name from Job.
+	if len(pod.Spec.SchedulerName) == 0 {
+		pod.Spec.SchedulerName = job.Spec.SchedulerName
+	}
+
+	volumeMap := make(map[string]bool)
+	for _, volume := range job.Spec.Volumes {
+		vcName := volume.VolumeClaimName
+		if _, ok := volumeMap[vcName]; !ok {
+			if _, ok := job.Status.ControlledResources["volume-emptyDir-"+vcName]; ok && volume.VolumeClaim == nil {
+				volume := v1.Volume{
+					Name: vcName,
+				}
+				volume.EmptyDir = &v1.EmptyDirVolumeSource{}
+				pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+			} else {
+				volume := v1.Volume{
+					Name: vcName,
+				}
+				volume.PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: vcName,
+				}
+				pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+			}
+			volumeMap[vcName] = true
+		}
+
+		for i, c := range pod.Spec.Containers {
+			vm := v1.VolumeMount{
+				MountPath: volume.MountPath,
+				Name:      vcName,
+			}
+			pod.Spec.Containers[i].VolumeMounts = append(c.VolumeMounts, vm)
+		}
+	}
+
+	if len(pod.Annotations) == 0 {
+		pod.Annotations = make(map[string]string)
+	}
+
+	tsKey := templateCopy.Name
+	if len(tsKey) == 0 {
+		tsKey = vkv1.DefaultTaskSpec
+	}
+
+	if len(pod.Annotations) == 0 {
+		pod.Annotations = make(map[string]string)
+	}
+
+	pod.Annotations[vkv1.TaskSpecKey] = tsKey
+	pod.Annotations[kbv1.GroupNameAnnotationKey] = job.Name
+	pod.Annotations[vkv1.JobNameKey] = job.Name
+	pod.Annotations[vkv1.JobVersion] = fmt.Sprintf("%d", job.Status.Version)
+
+	if len(pod.Labels) == 0 {
+		pod.Labels = make(map[string]string)
+	}
+
+	// Set pod labels for Service.
+	pod.Labels[vkv1.JobNameKey] = job.Name
+	pod.Labels[vkv1.JobNamespaceKey] = job.Namespace
+
+	// we fill the schedulerName in the pod definition with the one specified in the QJ template
+	if job.Spec.SchedulerName != "" && pod.Spec.SchedulerName == "" {
+		pod.Spec.SchedulerName = job.Spec.SchedulerName
+	}
+
+	return pod
+}
+
+// NewPVC returns the PVC of the volume claim of job.
+func NewPVC(job *vkv1.Job, vcName string, volumeClaim *v1.PersistentVolumeClaimSpec) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: job.Namespace,
+			Name:      vcName,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, apishelpers.JobKind),
+			},
+		},
+		Spec: *volumeClaim,
+	}
+}
+
+// PrepareJobVolumes generates the claim names of the volumes without one, creates the PVCs of the volumes
+// with a claim spec by createPVC if they do not exist, and records the volumes in the controlled resources
+// of job; the volumes without claim spec whose claim does not exist are mounted as emptyDir. It returns
+// whether the claim names of volumes are generated.
+func PrepareJobVolumes(job *vkv1.Job, pvcExists func(vcName string) (bool, error),
+	createPVC func(vcName string, volumeClaim *v1.PersistentVolumeClaimSpec) error) (bool, error) {
+	var needUpdate, nameExist bool
+	volumes := job.Spec.Volumes
+	for index, volume := range volumes {
+		nameExist = false
+		vcName := volume.VolumeClaimName
+		if len(vcName) == 0 {
+			//NOTE(k82cn): Ensure never have duplicated generated names.
+			for {
+				vcName = MakeVolumeClaimName(job.Name)
+				exist, err := pvcExists(vcName)
+				if err != nil {
+					return false, err
+				}
+				if exist {
+					continue
+				}
+				job.Spec.Volumes[index].VolumeClaimName = vcName
+				needUpdate = true
+				break
+			}
+		} else {
+			exist, err := pvcExists(vcName)
+			if err != nil {
+				return false, err
+			}
+			nameExist = exist
+		}
+
+		if !nameExist {
+			if job.Status.ControlledResources == nil {
+				job.Status.ControlledResources = make(map[string]string)
+			}
+			if volume.VolumeClaim != nil {
+				if err := createPVC(vcName, volume.VolumeClaim); err != nil {
+					return false, err
+				}
+				job.Status.ControlledResources["volume-pvc-"+vcName] = vcName
+			} else {
+				job.Status.ControlledResources["volume-emptyDir-"+vcName] = vcName
+			}
+		}
+	}
+	return needUpdate, nil
+}
+
+// NewPodGroup returns the PodGroup of job; the priority classes are used to calculate its minimal resources.
+func NewPodGroup(job *vkv1.Job, priorityClasses map[string]*v1beta1.PriorityClass) *kbv1.PodGroup {
+	return &kbv1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   job.Namespace,
+			Name:        job.Name,
+			Labels:      job.Labels,
+			Annotations: job.Annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, apishelpers.JobKind),
+			},
+		},
+		Spec: kbv1.PodGroupSpec{
+			MinMember:         job.Spec.MinAvailable,
+			Queue:             job.Spec.Queue,
+			MinResources:      CalcPGMinResources(job, priorityClasses),
+			PriorityClassName: job.Spec.PriorityClassName,
+		},
+	}
+}
+
+// CalcPGMinResources returns the resource requests of the minAvailable pods of job, the pods of tasks
+// with higher priority class are taken first.
+func CalcPGMinResources(job *vkv1.Job, priorityClasses map[string]*v1beta1.PriorityClass) *v1.ResourceList {
+	// sort task by priorityClasses
+	var tasksPriority TasksPriority
+	for index := range job.Spec.Tasks {
+		tp := TaskPriority{0, job.Spec.Tasks[index]}
+		pc := job.Spec.Tasks[index].Template.Spec.PriorityClassName
+		if len(priorityClasses) != 0 && priorityClasses[pc] != nil {
+			tp.priority = priorityClasses[pc].Value
+		}
+		tasksPriority = append(tasksPriority, tp)
+	}
+
+	sort.Sort(tasksPriority)
+
+	minAvailableTasksRes := v1.ResourceList{}
+	podCnt := int32(0)
+	for _, task := range tasksPriority {
+		for i := int32(0); i < task.Replicas; i++ {
+			if podCnt >= job.Spec.MinAvailable {
+				break
+			}
+			podCnt++
+			for _, c := range task.Template.Spec.Containers {
+				addResourceList(minAvailableTasksRes, c.Resources.Requests)
+			}
+		}
+	}
+
+	return &minAvailableTasksRes
+}
+
+func addResourceList(list, new v1.ResourceList) {
+	for name, quantity := range new {
+		if value, ok := list[name]; !ok {
+			list[name] = *quantity.Copy()
+		} else {
+			value.Add(quantity)
+			list[name] = value
+		}
+	}
+}
+
+//TaskPriority structure
+type TaskPriority struct {
+	priority int32
+
+	vkv1.TaskSpec
+}
+
+//TasksPriority is a slice of TaskPriority
+type TasksPriority []TaskPriority
+
+func (p TasksPriority) Len() int { return len(p) }
+
+func (p TasksPriority) Less(i, j int) bool {
+	return p[i].priority > p[j].priority
+}
+
+func (p TasksPriority) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
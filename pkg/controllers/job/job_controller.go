@@ -24,6 +24,7 @@ import (
 
 	"k8s.io/api/core/v1"
 	"k8s.io/api/scheduling/v1beta1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	coreinformers "k8s.io/client-go/informers/core/v1"
@@ -104,6 +105,11 @@ type Controller struct {
 
 	sync.Mutex
 	errTasks workqueue.RateLimitingInterface
+
+	// the pods deleted to restart their tasks, whose deletion or failure
+	// is not handled by the policies of job
+	restartedPods     sets.String
+	restartedPodsLock sync.Mutex
 }
 
 // NewJobController create new Job Controller
@@ -129,6 +135,7 @@ func NewJobController(
 		errTasks:        newRateLimitingQueue(),
 		recorder:        recorder,
 		priorityClasses: make(map[string]*v1beta1.PriorityClass),
+		restartedPods:   sets.NewString(),
 	}
 
 	cc.jobInformer = vkinfoext.NewSharedInformerFactory(cc.vkClients, 0).Batch().V1alpha1().Jobs()
@@ -255,7 +262,14 @@ func (cc *Controller) processNextReq() bool {
 			"Start to execute action %s ", action))
 	}
 
-	if err := st.Execute(action); err != nil {
+	if action == vkbatchv1.RestartTaskAction {
+		// Restarting a task does not change the state of job, the pods of task
+		// are created again when syncing the job.
+		err = cc.restartTask(jobInfo, req.TaskName)
+	} else {
+		err = st.Execute(action)
+	}
+	if err != nil {
 		glog.Errorf("Failed to handle Job <%s/%s>: %v",
 			jobInfo.Job.Namespace, jobInfo.Job.Name, err)
 		// If any error, requeue it.
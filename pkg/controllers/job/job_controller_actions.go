@@ -18,7 +18,6 @@ package job
 
 import (
 	"fmt"
-	"sort"
 	"sync"
 
 	"github.com/golang/glog"
@@ -29,7 +28,6 @@ import (
 
 	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
-	"volcano.sh/volcano/pkg/apis/helpers"
 	"volcano.sh/volcano/pkg/controllers/apis"
 	vkjobhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
 	"volcano.sh/volcano/pkg/controllers/job/state"
@@ -147,6 +145,52 @@ func (cc *Controller) killJob(jobInfo *apis.JobInfo, podRetainPhase state.PhaseM
 	return nil
 }
 
+// restartTask deletes the pods of task, which are created again when syncing the job. Unlike killJob,
+// the version of job is not bumped and the deletion of pods is not handled by the policies of job.
+func (cc *Controller) restartTask(jobInfo *apis.JobInfo, taskName string) error {
+	job := jobInfo.Job
+	if job.DeletionTimestamp != nil {
+		glog.Infof("Job <%s/%s> is terminating, skip restarting task %s.",
+			job.Namespace, job.Name, taskName)
+		return nil
+	}
+
+	switch job.Status.State.Phase {
+	case vkv1.Pending, vkv1.Inqueue, vkv1.Running:
+	default:
+		glog.Infof("Job <%s/%s> is %s, skip restarting task %s.",
+			job.Namespace, job.Name, job.Status.State.Phase, taskName)
+		return nil
+	}
+
+	pods, found := jobInfo.Pods[taskName]
+	if !found {
+		glog.Warningf("No pods of task %s found in Job <%s/%s>, skip restarting it.",
+			taskName, job.Namespace, job.Name)
+		return nil
+	}
+
+	var errs []error
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		cc.markRestartedPod(pod)
+		if err := cc.deleteJobPod(job.Name, pod); err != nil {
+			cc.forgetRestartedPod(pod)
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) != 0 {
+		glog.Errorf("failed to restart task %s of job %s/%s, with err %+v", taskName, job.Namespace, job.Name, errs)
+		return fmt.Errorf("failed to delete %d pods of task %s", len(errs), taskName)
+	}
+
+	return nil
+}
+
 func (cc *Controller) createJob(jobInfo *apis.JobInfo, updateStatus state.UpdateStatusFn) error {
 	glog.V(3).Infof("Starting to create Job <%s/%s>", jobInfo.Job.Namespace, jobInfo.Job.Name)
 	defer glog.V(3).Infof("Finished Job <%s/%s> create", jobInfo.Job.Namespace, jobInfo.Job.Name)
@@ -227,7 +271,7 @@ func (cc *Controller) syncJob(jobInfo *apis.JobInfo, updateStatus state.UpdateSt
 		for i := 0; i < int(ts.Replicas); i++ {
 			podName := fmt.Sprintf(vkjobhelpers.PodNameFmt, job.Name, name, i)
 			if pod, found := pods[podName]; !found {
-				newPod := createJobPod(job, tc, i)
+				newPod := vkjobhelpers.CreateJobPod(job, tc, i)
 				if err := cc.pluginOnPodCreate(job, newPod); err != nil {
 					return err
 				}
@@ -354,47 +398,13 @@ func (cc *Controller) syncJob(jobInfo *apis.JobInfo, updateStatus state.UpdateSt
 
 func (cc *Controller) createJobIOIfNotExist(job *vkv1.Job) (*vkv1.Job, error) {
 	// If PVC does not exist, create them for Job.
-	var needUpdate, nameExist bool
-	volumes := job.Spec.Volumes
-	for index, volume := range volumes {
-		nameExist = false
-		vcName := volume.VolumeClaimName
-		if len(vcName) == 0 {
-			//NOTE(k82cn): Ensure never have duplicated generated names.
-			for {
-				vcName = vkjobhelpers.MakeVolumeClaimName(job.Name)
-				exist, err := cc.checkPVCExist(job, vcName)
-				if err != nil {
-					return nil, err
-				}
-				if exist {
-					continue
-				}
-				job.Spec.Volumes[index].VolumeClaimName = vcName
-				needUpdate = true
-				break
-			}
-		} else {
-			exist, err := cc.checkPVCExist(job, vcName)
-			if err != nil {
-				return nil, err
-			}
-			nameExist = exist
-		}
-
-		if !nameExist {
-			if job.Status.ControlledResources == nil {
-				job.Status.ControlledResources = make(map[string]string)
-			}
-			if volume.VolumeClaim != nil {
-				if err := cc.createPVC(job, vcName, volume.VolumeClaim); err != nil {
-					return nil, err
-				}
-				job.Status.ControlledResources["volume-pvc-"+vcName] = vcName
-			} else {
-				job.Status.ControlledResources["volume-emptyDir-"+vcName] = vcName
-			}
-		}
+	needUpdate, err := vkjobhelpers.PrepareJobVolumes(job, func(vcName string) (bool, error) {
+		return cc.checkPVCExist(job, vcName)
+	}, func(vcName string, volumeClaim *v1.PersistentVolumeClaimSpec) error {
+		return cc.createPVC(job, vcName, volumeClaim)
+	})
+	if err != nil {
+		return nil, err
 	}
 	if needUpdate {
 		newJob, err := cc.vkClients.BatchV1alpha1().Jobs(job.Namespace).Update(job)
@@ -421,16 +431,7 @@ func (cc *Controller) checkPVCExist(job *vkv1.Job, vcName string) (bool, error)
 }
 
 func (cc *Controller) createPVC(job *vkv1.Job, vcName string, volumeClaim *v1.PersistentVolumeClaimSpec) error {
-	pvc := &v1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: job.Namespace,
-			Name:      vcName,
-			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(job, helpers.JobKind),
-			},
-		},
-		Spec: *volumeClaim,
-	}
+	pvc := vkjobhelpers.NewPVC(job, vcName, volumeClaim)
 
 	glog.V(3).Infof("Try to create PVC: %v", pvc)
 
@@ -450,24 +451,7 @@ func (cc *Controller) createPodGroupIfNotExist(job *vkv1.Job) error {
 				job.Namespace, job.Name, err)
 			return err
 		}
-		pg := &kbv1.PodGroup{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace:   job.Namespace,
-				Name:        job.Name,
-				Annotations: job.Annotations,
-				OwnerReferences: []metav1.OwnerReference{
-					*metav1.NewControllerRef(job, helpers.JobKind),
-				},
-			},
-			Spec: kbv1.PodGroupSpec{
-				MinMember:         job.Spec.MinAvailable,
-				Queue:             job.Spec.Queue,
-				MinResources:      cc.calcPGMinResources(job),
-				PriorityClassName: job.Spec.PriorityClassName,
-			},
-		}
-
-		if _, e := cc.kbClients.SchedulingV1alpha1().PodGroups(job.Namespace).Create(pg); e != nil {
+		if _, e := cc.kbClients.SchedulingV1alpha1().PodGroups(job.Namespace).Create(cc.newPodGroup(job)); e != nil {
 			glog.V(3).Infof("Failed to create PodGroup for Job <%s/%s>: %v",
 				job.Namespace, job.Name, err)
 
@@ -478,6 +462,13 @@ func (cc *Controller) createPodGroupIfNotExist(job *vkv1.Job) error {
 	return nil
 }
 
+func (cc *Controller) newPodGroup(job *vkv1.Job) *kbv1.PodGroup {
+	cc.Mutex.Lock()
+	defer cc.Mutex.Unlock()
+
+	return vkjobhelpers.NewPodGroup(job, cc.priorityClasses)
+}
+
 func (cc *Controller) deleteJobPod(jobName string, pod *v1.Pod) error {
 	err := cc.kubeClients.CoreV1().Pods(pod.Namespace).Delete(pod.Name, nil)
 	if err != nil && !apierrors.IsNotFound(err) {
@@ -489,37 +480,3 @@ func (cc *Controller) deleteJobPod(jobName string, pod *v1.Pod) error {
 
 	return nil
 }
-
-func (cc *Controller) calcPGMinResources(job *vkv1.Job) *v1.ResourceList {
-	cc.Mutex.Lock()
-	defer cc.Mutex.Unlock()
-
-	// sort task by priorityClasses
-	var tasksPriority TasksPriority
-	for index := range job.Spec.Tasks {
-		tp := TaskPriority{0, job.Spec.Tasks[index]}
-		pc := job.Spec.Tasks[index].Template.Spec.PriorityClassName
-		if len(cc.priorityClasses) != 0 && cc.priorityClasses[pc] != nil {
-			tp.priority = cc.priorityClasses[pc].Value
-		}
-		tasksPriority = append(tasksPriority, tp)
-	}
-
-	sort.Sort(tasksPriority)
-
-	minAvailableTasksRes := v1.ResourceList{}
-	podCnt := int32(0)
-	for _, task := range tasksPriority {
-		for i := int32(0); i < task.Replicas; i++ {
-			if podCnt >= job.Spec.MinAvailable {
-				break
-			}
-			podCnt++
-			for _, c := range task.Template.Spec.Containers {
-				addResourceList(minAvailableTasksRes, c.Resources.Requests)
-			}
-		}
-	}
-
-	return &minAvailableTasksRes
-}
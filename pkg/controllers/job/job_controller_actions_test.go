@@ -21,6 +21,7 @@ import (
 	kbv1aplha1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
 	"testing"
 	"volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/controllers/apis"
@@ -470,8 +471,10 @@ func TestCreatePodGroupIfNotExistFunc(t *testing.T) {
 			Name: "CreatePodGroup success Case",
 			Job: &v1alpha1.Job{
 				ObjectMeta: metav1.ObjectMeta{
-					Namespace: namespace,
-					Name:      "job1",
+					Namespace:   namespace,
+					Name:        "job1",
+					Labels:      map[string]string{"team": "research"},
+					Annotations: map[string]string{v1alpha1.JobUserKey: "alice"},
 				},
 			},
 			ExpextVal: nil,
@@ -486,9 +489,15 @@ func TestCreatePodGroupIfNotExistFunc(t *testing.T) {
 			t.Errorf("Expected return value to be equal to expected: %s, but got: %s", testcase.ExpextVal, err)
 		}
 
-		_, err = fakeController.kbClients.SchedulingV1alpha1().PodGroups(namespace).Get(testcase.Job.Name, metav1.GetOptions{})
+		pg, err := fakeController.kbClients.SchedulingV1alpha1().PodGroups(namespace).Get(testcase.Job.Name, metav1.GetOptions{})
 		if err != nil {
 			t.Error("Expected PodGroup to get created, but not created")
+			continue
+		}
+		// The scheduler groups jobs by the labels and annotations of their PodGroups.
+		if !reflect.DeepEqual(pg.Labels, testcase.Job.Labels) || !reflect.DeepEqual(pg.Annotations, testcase.Job.Annotations) {
+			t.Errorf("Expected labels %v and annotations %v of PodGroup, but got %v and %v",
+				testcase.Job.Labels, testcase.Job.Annotations, pg.Labels, pg.Annotations)
 		}
 	}
 }
@@ -541,3 +550,88 @@ func TestDeleteJobPod(t *testing.T) {
 		}
 	}
 }
+
+func TestRestartTask(t *testing.T) {
+	namespace := "test"
+
+	testcases := []struct {
+		Name          string
+		Phase         v1alpha1.JobPhase
+		ExpectDeleted []string
+	}{
+		{
+			Name:          "RestartTask of running job",
+			Phase:         v1alpha1.Running,
+			ExpectDeleted: []string{"job1-worker-0"},
+		},
+		{
+			Name:          "RestartTask of completed job",
+			Phase:         v1alpha1.Completed,
+			ExpectDeleted: nil,
+		},
+	}
+
+	for i, testcase := range testcases {
+		fakeController := newFakeController()
+
+		annotations := map[string]string{
+			v1alpha1.JobNameKey: "job1",
+			v1alpha1.JobVersion: "1",
+		}
+		jobInfo := &apis.JobInfo{
+			Namespace: namespace,
+			Name:      "job1",
+			Job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "job1",
+					Namespace: namespace,
+				},
+				Status: v1alpha1.JobStatus{
+					State:   v1alpha1.JobState{Phase: testcase.Phase},
+					Version: 1,
+				},
+			},
+			Pods: map[string]map[string]*v1.Pod{},
+		}
+		for _, task := range []string{"worker", "ps"} {
+			pod := buildPod(namespace, fmt.Sprintf("job1-%s-0", task), v1.PodRunning, nil)
+			addPodAnnotation(pod, annotations)
+			addPodAnnotation(pod, map[string]string{v1alpha1.TaskSpecKey: task})
+			if _, err := fakeController.kubeClients.CoreV1().Pods(namespace).Create(pod); err != nil {
+				t.Fatalf("case %d (%s): failed to create pod: %v", i, testcase.Name, err)
+			}
+			jobInfo.Pods[task] = map[string]*v1.Pod{pod.Name: pod}
+		}
+
+		if err := fakeController.restartTask(jobInfo, "worker"); err != nil {
+			t.Errorf("case %d (%s): expected no error, got %v", i, testcase.Name, err)
+		}
+
+		var deleted []string
+		for _, pods := range jobInfo.Pods {
+			for _, pod := range pods {
+				_, err := fakeController.kubeClients.CoreV1().Pods(namespace).Get(pod.Name, metav1.GetOptions{})
+				if err == nil {
+					continue
+				}
+				deleted = append(deleted, pod.Name)
+
+				// The deletion of restarted pod only syncs the job instead of evicting it.
+				fakeController.deletePod(pod)
+				req, _ := fakeController.queue.Get()
+				if event := req.(apis.Request).Event; event != v1alpha1.OutOfSyncEvent {
+					t.Errorf("case %d (%s): expected event %s of deleting pod %s, got %s",
+						i, testcase.Name, v1alpha1.OutOfSyncEvent, pod.Name, event)
+				}
+				fakeController.queue.Done(req)
+			}
+		}
+		if !reflect.DeepEqual(deleted, testcase.ExpectDeleted) {
+			t.Errorf("case %d (%s): expected deleted pods %v, got %v", i, testcase.Name, testcase.ExpectDeleted, deleted)
+		}
+		if fakeController.restartedPods.Len() != 0 {
+			t.Errorf("case %d (%s): expected restarted pods forgotten, got %v",
+				i, testcase.Name, fakeController.restartedPods.List())
+		}
+	}
+}
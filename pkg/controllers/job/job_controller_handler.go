@@ -231,6 +231,11 @@ func (cc *Controller) updatePod(oldObj, newObj interface{}) {
 		}
 	}
 
+	// The pod deleted to restart its task may fail when killed, which is not a failure of job.
+	if event == vkbatchv1.PodFailedEvent && cc.isRestartedPod(newPod) {
+		event = vkbatchv1.OutOfSyncEvent
+	}
+
 	req := apis.Request{
 		Namespace: newPod.Namespace,
 		JobName:   jobName,
@@ -288,12 +293,19 @@ func (cc *Controller) deletePod(obj interface{}) {
 		return
 	}
 
+	event := vkbatchv1.PodEvictedEvent
+	// The pod deleted to restart its task is not evicted, it is created again when syncing the job.
+	if cc.isRestartedPod(pod) {
+		event = vkbatchv1.OutOfSyncEvent
+		cc.forgetRestartedPod(pod)
+	}
+
 	req := apis.Request{
 		Namespace: pod.Namespace,
 		JobName:   jobName,
 		TaskName:  taskName,
 
-		Event:      vkbatchv1.PodEvictedEvent,
+		Event:      event,
 		JobVersion: int32(dVersion),
 	}
 
@@ -336,15 +348,19 @@ func (cc *Controller) processNextCommand() bool {
 		}
 		return true
 	}
-	cc.recordJobEvent(cmd.Namespace, cmd.TargetObject.Name,
-		vkbatchv1.CommandIssued,
-		fmt.Sprintf(
-			"Start to execute command %s, and clean it up to make sure executed not more than once.", cmd.Action))
+	message := fmt.Sprintf(
+		"Start to execute command %s, and clean it up to make sure executed not more than once.", cmd.Action)
+	if len(cmd.Reason) != 0 || len(cmd.Message) != 0 {
+		message = fmt.Sprintf("%s Reason: %s, message: %s", message, cmd.Reason, cmd.Message)
+	}
+	cc.recordJobEvent(cmd.Namespace, cmd.TargetObject.Name, vkbatchv1.CommandIssued, message)
 	req := apis.Request{
 		Namespace: cmd.Namespace,
 		JobName:   cmd.TargetObject.Name,
-		Event:     vkbatchv1.CommandIssuedEvent,
-		Action:    vkbatchv1.Action(cmd.Action),
+		// The task to restart by RestartTask command.
+		TaskName: cmd.Annotations[vkbatchv1.TaskSpecKey],
+		Event:    vkbatchv1.CommandIssuedEvent,
+		Action:   vkbatchv1.Action(cmd.Action),
 	}
 
 	cc.queue.Add(req)
@@ -18,6 +18,7 @@ package env
 
 import (
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	vkhelpers "volcano.sh/volcano/pkg/controllers/job/helpers"
@@ -68,3 +69,7 @@ func (ep *envPlugin) OnJobAdd(job *vkv1.Job) error {
 func (ep *envPlugin) OnJobDelete(job *vkv1.Job) error {
 	return nil
 }
+
+func (ep *envPlugin) ValidateJob(job *vkv1.Job) field.ErrorList {
+	return nil
+}
@@ -18,6 +18,7 @@ package pluginsinterface
 
 import (
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/kubernetes"
 
 	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
@@ -41,4 +42,8 @@ type PluginInterface interface {
 
 	// do once when killJob
 	OnJobDelete(job *vkv1.Job) error
+
+	// for admission of job, the requirements of Plugin are checked before any pod is created;
+	// the clientset is not set when validating.
+	ValidateJob(job *vkv1.Job) field.ErrorList
 }
@@ -29,6 +29,7 @@ import (
 	"github.com/golang/glog"
 
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/apis/helpers"
@@ -45,6 +46,8 @@ type sshPlugin struct {
 
 	// flag parse args
 	noRoot bool
+	// the error of parsing arguments, reported when validating job
+	argsErr error
 }
 
 // New creates ssh plugin
@@ -93,11 +96,25 @@ func (sp *sshPlugin) OnJobDelete(job *vkv1.Job) error {
 	return nil
 }
 
-func (sp *sshPlugin) mountRsaKey(pod *v1.Pod, job *vkv1.Job) {
-	sshPath := SSHAbsolutePath
+func (sp *sshPlugin) ValidateJob(job *vkv1.Job) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if sp.argsErr != nil {
+		pluginPath := field.NewPath("spec").Child("plugins").Key(sp.Name())
+		return append(allErrs, field.Invalid(pluginPath, sp.pluginArguments, sp.argsErr.Error()))
+	}
+
+	return append(allErrs, vkhelpers.ValidateMountPath(job, sp.sshPath(), sp.Name())...)
+}
+
+func (sp *sshPlugin) sshPath() string {
 	if sp.noRoot {
-		sshPath = env.ConfigMapMountPath + "/" + SSHRelativePath
+		return env.ConfigMapMountPath + "/" + SSHRelativePath
 	}
+	return SSHAbsolutePath
+}
+
+func (sp *sshPlugin) mountRsaKey(pod *v1.Pod, job *vkv1.Job) {
+	sshPath := sp.sshPath()
 
 	cmName := sp.cmName(job)
 	sshVolume := v1.Volume{
@@ -191,6 +208,9 @@ func (sp *sshPlugin) addFlags() {
 
 	if err := flagSet.Parse(sp.pluginArguments); err != nil {
 		glog.Errorf("plugin %s flagset parse failed, err: %v", sp.Name(), err)
+		sp.argsErr = err
+	} else if flagSet.NArg() != 0 {
+		sp.argsErr = fmt.Errorf("unexpected arguments %v", flagSet.Args())
 	}
 	return
 }
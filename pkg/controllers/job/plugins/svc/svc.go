@@ -26,6 +26,8 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
 	"volcano.sh/volcano/pkg/apis/helpers"
@@ -100,6 +102,34 @@ func (sp *servicePlugin) OnJobDelete(job *vkv1.Job) error {
 	return nil
 }
 
+func (sp *servicePlugin) ValidateJob(job *vkv1.Job) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// the headless service is named after the job, which is also the default subdomain of pods
+	namePath := field.NewPath("metadata").Child("name")
+	for _, msg := range validation.IsDNS1035Label(job.Name) {
+		allErrs = append(allErrs, field.Invalid(namePath, job.Name,
+			fmt.Sprintf("invalid name of service created by svc plugin: %s", msg)))
+	}
+
+	tasksPath := field.NewPath("spec").Child("tasks")
+	for i, ts := range job.Spec.Tasks {
+		if len(ts.Template.Spec.Hostname) != 0 || ts.Replicas <= 0 {
+			continue
+		}
+		// the hostname of the last pod is the longest one
+		hostName := vkhelpers.MakePodName(job.Name, ts.Name, int(ts.Replicas)-1)
+		for _, msg := range validation.IsDNS1123Label(hostName) {
+			allErrs = append(allErrs, field.Invalid(tasksPath.Index(i).Child("name"), ts.Name,
+				fmt.Sprintf("invalid hostname %s generated by svc plugin: %s", hostName, msg)))
+		}
+	}
+
+	allErrs = append(allErrs, vkhelpers.ValidateMountPath(job, ConfigMapMountPath, sp.Name())...)
+
+	return allErrs
+}
+
 func (sp *servicePlugin) mountConfigmap(pod *v1.Pod, job *vkv1.Job) {
 	cmName := sp.cmName(job)
 	cmVolume := v1.Volume{
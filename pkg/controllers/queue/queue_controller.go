@@ -17,20 +17,41 @@ limitations under the License.
 package queue
 
 import (
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	kbv1alpha1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
 	kbclientset "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned"
+	kbscheme "github.com/kubernetes-sigs/kube-batch/pkg/client/clientset/versioned/scheme"
 	kbinformerfactory "github.com/kubernetes-sigs/kube-batch/pkg/client/informers/externalversions"
 	kbinformer "github.com/kubernetes-sigs/kube-batch/pkg/client/informers/externalversions/scheduling/v1alpha1"
 	kblister "github.com/kubernetes-sigs/kube-batch/pkg/client/listers/scheduling/v1alpha1"
+
+	vkbatchv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
+)
+
+const (
+	// QueueLimitExceeded event is generated if the jobs in queue exceed its limits
+	QueueLimitExceeded = "QueueLimitExceeded"
+	// QueueNotFound event is generated if the queue of PodGroup does not exist
+	QueueNotFound = "QueueNotFound"
+
+	// resyncPeriod is the period to rebuild the PodGroup index of queues from lister
+	resyncPeriod = 5 * time.Minute
 )
 
 // Controller manages queue status.
@@ -53,6 +74,9 @@ type Controller struct {
 	// queues that need to be updated.
 	queue workqueue.RateLimitingInterface
 
+	// Queue Event recorder
+	recorder record.EventRecorder
+
 	pgMutex   sync.RWMutex
 	podGroups map[string]map[string]struct{}
 }
@@ -62,6 +86,11 @@ func NewQueueController(
 	kubeClient kubernetes.Interface,
 	kbClient kbclientset.Interface,
 ) *Controller {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(glog.Infof)
+	eventBroadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(kbscheme.Scheme, v1.EventSource{Component: "vk-controller"})
+
 	factory := kbinformerfactory.NewSharedInformerFactory(kbClient, 0)
 	queueInformer := factory.Scheduling().V1alpha1().Queues()
 	pgInformer := factory.Scheduling().V1alpha1().PodGroups()
@@ -79,6 +108,7 @@ func NewQueueController(
 		pgSynced: pgInformer.Informer().HasSynced,
 
 		queue:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		recorder:  recorder,
 		podGroups: make(map[string]map[string]struct{}),
 	}
 
@@ -108,6 +138,7 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	}
 
 	go wait.Until(c.worker, 0, stopCh)
+	go wait.Until(c.resyncPodGroups, resyncPeriod, stopCh)
 	glog.Infof("QueueController is running ...... ")
 }
 
@@ -140,27 +171,30 @@ func (c *Controller) processNextWorkItem() bool {
 func (c *Controller) syncQueue(key string) error {
 	glog.V(4).Infof("Begin sync queue %s", key)
 
-	var pending, running, unknown int32
+	var pending, running, unknown, inqueue int32
+	// A queue without PodGroups is synced with zero counts, e.g. its last PodGroup was dropped
+	// by resync; the deleted queues are skipped when getting them from lister below.
 	c.pgMutex.RLock()
-	if c.podGroups[key] == nil {
-		c.pgMutex.RUnlock()
-		glog.V(2).Infof("queue %s has not been seen or deleted", key)
-		return nil
-	}
 	podGroups := make([]string, 0, len(c.podGroups[key]))
 	for pgKey := range c.podGroups[key] {
 		podGroups = append(podGroups, pgKey)
 	}
 	c.pgMutex.RUnlock()
 
+	var pgs []*kbv1alpha1.PodGroup
 	for _, pgKey := range podGroups {
 		// Ignore error here, tt can not occur.
 		ns, name, _ := cache.SplitMetaNamespaceKey(pgKey)
 
 		pg, err := c.pgLister.PodGroups(ns).Get(name)
 		if err != nil {
+			if errors.IsNotFound(err) {
+				glog.V(4).Infof("PodGroup %s of queue %s has been deleted", pgKey, key)
+				continue
+			}
 			return err
 		}
+		pgs = append(pgs, pg)
 
 		switch pg.Status.Phase {
 		case kbv1alpha1.PodGroupPending:
@@ -169,6 +203,8 @@ func (c *Controller) syncQueue(key string) error {
 			running++
 		case kbv1alpha1.PodGroupUnknown:
 			unknown++
+		case kbv1alpha1.PodGroupInqueue:
+			inqueue++
 		}
 	}
 
@@ -176,12 +212,21 @@ func (c *Controller) syncQueue(key string) error {
 	if err != nil {
 		if errors.IsNotFound(err) {
 			glog.V(2).Infof("queue %s has been deleted", key)
+			for _, pg := range pgs {
+				c.recorder.Event(pg, v1.EventTypeWarning, QueueNotFound,
+					fmt.Sprintf("Queue %s of PodGroup does not exist", key))
+			}
 			return nil
 		}
 		return err
 	}
 
-	glog.V(4).Infof("queue %s jobs pending %d, running %d, unknown %d", key, pending, running, unknown)
+	glog.V(4).Infof("queue %s jobs pending %d, running %d, unknown %d, inqueue %d", key, pending, running, unknown, inqueue)
+
+	// Jobs which have been enqueued are counted against the running limit,
+	// the same as the scheduler does.
+	c.checkQueueLimits(queue, pending, running+unknown+inqueue)
+
 	// ignore update when status doesnot change
 	if pending == queue.Status.Pending && running == queue.Status.Running && unknown == queue.Status.Unknown {
 		return nil
@@ -200,6 +245,20 @@ func (c *Controller) syncQueue(key string) error {
 	return nil
 }
 
+// checkQueueLimits records an event if the usage of queue exceeds the limits set by annotations,
+// e.g. the limits were lowered after jobs were submitted.
+func (c *Controller) checkQueueLimits(queue *kbv1alpha1.Queue, pending, running int32) {
+	if limit, found := helpers.GetQueueJobLimit(queue, vkbatchv1.QueueMaxPendingJobsKey); found && pending > limit {
+		c.recorder.Event(queue, v1.EventTypeWarning, QueueLimitExceeded,
+			fmt.Sprintf("%d pending jobs exceed the limit %d", pending, limit))
+	}
+
+	if limit, found := helpers.GetQueueJobLimit(queue, vkbatchv1.QueueMaxRunningJobsKey); found && running > limit {
+		c.recorder.Event(queue, v1.EventTypeWarning, QueueLimitExceeded,
+			fmt.Sprintf("%d running jobs exceed the limit %d", running, limit))
+	}
+}
+
 func (c *Controller) addQueue(obj interface{}) {
 	queue := obj.(*kbv1alpha1.Queue)
 	c.queue.Add(queue.Name)
@@ -244,13 +303,27 @@ func (c *Controller) updatePodGroup(old, new interface{}) {
 	oldPG := old.(*kbv1alpha1.PodGroup)
 	newPG := new.(*kbv1alpha1.PodGroup)
 
-	// Note: we have no use case update PodGroup.Spec.Queue
-	// So do not consider it here.
+	if oldPG.Spec.Queue != newPG.Spec.Queue {
+		key, _ := cache.MetaNamespaceKeyFunc(newPG)
+
+		c.pgMutex.Lock()
+		delete(c.podGroups[oldPG.Spec.Queue], key)
+		if c.podGroups[newPG.Spec.Queue] == nil {
+			c.podGroups[newPG.Spec.Queue] = make(map[string]struct{})
+		}
+		c.podGroups[newPG.Spec.Queue][key] = struct{}{}
+		c.pgMutex.Unlock()
+
+		// enqueue both queues to update their status
+		c.queue.Add(oldPG.Spec.Queue)
+		c.queue.Add(newPG.Spec.Queue)
+		return
+	}
+
 	if oldPG.Status.Phase != newPG.Status.Phase {
 		// enqueue
 		c.queue.Add(newPG.Spec.Queue)
 	}
-
 }
 
 func (c *Controller) deletePodGroup(obj interface{}) {
@@ -276,3 +349,54 @@ func (c *Controller) deletePodGroup(obj interface{}) {
 
 	c.queue.Add(pg.Spec.Queue)
 }
+
+// resyncPodGroups rebuilds the PodGroup index of queues from lister, in case of
+// any missed event, and enqueues all related queues.
+func (c *Controller) resyncPodGroups() {
+	// Hold the lock while listing, so the PodGroups indexed by event handlers meanwhile
+	// are not overwritten by the stale list.
+	c.pgMutex.Lock()
+	defer c.pgMutex.Unlock()
+
+	pgs, err := c.pgLister.List(labels.Everything())
+	if err != nil {
+		glog.Errorf("Failed to list PodGroups for resync: %v", err)
+		return
+	}
+
+	podGroups := make(map[string]map[string]struct{})
+	for _, pg := range pgs {
+		key, err := cache.MetaNamespaceKeyFunc(pg)
+		if err != nil {
+			glog.Errorf("Failed to get key of PodGroup <%s/%s>: %v", pg.Namespace, pg.Name, err)
+			continue
+		}
+		if podGroups[pg.Spec.Queue] == nil {
+			podGroups[pg.Spec.Queue] = make(map[string]struct{})
+		}
+		podGroups[pg.Spec.Queue][key] = struct{}{}
+	}
+
+	// The queues without PodGroups are also synced, so their counts are reset.
+	queues := sets.NewString()
+	for queue := range c.podGroups {
+		queues.Insert(queue)
+	}
+	for queue := range podGroups {
+		queues.Insert(queue)
+	}
+	if allQueues, err := c.queueLister.List(labels.Everything()); err != nil {
+		glog.Errorf("Failed to list Queues for resync: %v", err)
+	} else {
+		for _, queue := range allQueues {
+			queues.Insert(queue.Name)
+		}
+	}
+	c.podGroups = podGroups
+
+	glog.V(4).Infof("Resynced %d PodGroups of %d queues", len(pgs), len(podGroups))
+
+	for queue := range queues {
+		c.queue.Add(queue)
+	}
+}
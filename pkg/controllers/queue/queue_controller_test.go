@@ -277,3 +277,154 @@ func TestSyncQueue(t *testing.T) {
 	}
 
 }
+
+func TestSyncQueueWithoutPodGroups(t *testing.T) {
+	c := newFakeController()
+
+	// The counts of queue are stale, e.g. its last PodGroup was deleted with the event missed.
+	queue := &kbv1alpha1.Queue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "c1",
+		},
+		Spec: kbv1alpha1.QueueSpec{
+			Weight: 1,
+		},
+		Status: kbv1alpha1.QueueStatus{
+			Pending: 1,
+			Running: 2,
+		},
+	}
+	c.queueInformer.Informer().GetIndexer().Add(queue)
+	c.kbClient.SchedulingV1alpha1().Queues().Create(queue)
+
+	if err := c.syncQueue(queue.Name); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	item, _ := c.kbClient.SchedulingV1alpha1().Queues().Get(queue.Name, metav1.GetOptions{})
+	if item.Status.Pending != 0 || item.Status.Running != 0 {
+		t.Errorf("expected counts of queue reset, got %v", item.Status)
+	}
+}
+
+func TestUpdatePodGroupQueue(t *testing.T) {
+	namespace := "c1"
+
+	testCases := []struct {
+		Name        string
+		podGroupold *kbv1alpha1.PodGroup
+		podGroupnew *kbv1alpha1.PodGroup
+		ExpectValue int
+	}{
+		{
+			Name: "updatepodgroupqueue",
+			podGroupold: &kbv1alpha1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pg1",
+					Namespace: namespace,
+				},
+				Spec: kbv1alpha1.PodGroupSpec{
+					Queue: "c1",
+				},
+			},
+			podGroupnew: &kbv1alpha1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pg1",
+					Namespace: namespace,
+				},
+				Spec: kbv1alpha1.PodGroupSpec{
+					Queue: "c2",
+				},
+			},
+			ExpectValue: 2,
+		},
+	}
+
+	for i, testcase := range testCases {
+		c := newFakeController()
+
+		key, _ := cache.MetaNamespaceKeyFunc(testcase.podGroupold)
+		c.podGroups[testcase.podGroupold.Spec.Queue] = make(map[string]struct{})
+		c.podGroups[testcase.podGroupold.Spec.Queue][key] = struct{}{}
+
+		c.updatePodGroup(testcase.podGroupold, testcase.podGroupnew)
+
+		if _, ok := c.podGroups[testcase.podGroupold.Spec.Queue][key]; ok {
+			t.Errorf("case %d (%s): expected PodGroup removed from old queue", i, testcase.Name)
+		}
+		if _, ok := c.podGroups[testcase.podGroupnew.Spec.Queue][key]; !ok {
+			t.Errorf("case %d (%s): expected PodGroup indexed under new queue", i, testcase.Name)
+		}
+		if testcase.ExpectValue != c.queue.Len() {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, c.queue.Len())
+		}
+	}
+}
+
+func TestResyncPodGroups(t *testing.T) {
+	namespace := "c1"
+
+	testCases := []struct {
+		Name        string
+		podGroups   []*kbv1alpha1.PodGroup
+		staleQueue  string
+		emptyQueue  string
+		ExpectValue map[string]int
+	}{
+		{
+			Name: "resyncpodgroups",
+			podGroups: []*kbv1alpha1.PodGroup{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg1",
+						Namespace: namespace,
+					},
+					Spec: kbv1alpha1.PodGroupSpec{
+						Queue: "c1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pg2",
+						Namespace: namespace,
+					},
+					Spec: kbv1alpha1.PodGroupSpec{
+						Queue: "c2",
+					},
+				},
+			},
+			staleQueue: "c3",
+			emptyQueue: "c4",
+			ExpectValue: map[string]int{
+				"c1": 1,
+				"c2": 1,
+			},
+		},
+	}
+
+	for i, testcase := range testCases {
+		c := newFakeController()
+
+		c.podGroups[testcase.staleQueue] = map[string]struct{}{namespace + "/pg1": {}}
+		c.queueInformer.Informer().GetIndexer().Add(&kbv1alpha1.Queue{
+			ObjectMeta: metav1.ObjectMeta{Name: testcase.emptyQueue},
+		})
+		for _, pg := range testcase.podGroups {
+			c.pgInformer.Informer().GetIndexer().Add(pg)
+		}
+
+		c.resyncPodGroups()
+
+		if len(testcase.ExpectValue) != len(c.podGroups) {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, c.podGroups)
+		}
+		for queue, count := range testcase.ExpectValue {
+			if count != len(c.podGroups[queue]) {
+				t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, c.podGroups)
+			}
+		}
+		// the stale queue and the queue without PodGroups should also be synced
+		if c.queue.Len() != len(testcase.ExpectValue)+2 {
+			t.Errorf("case %d (%s): expected %d queues enqueued, got %v ", i, testcase.Name, len(testcase.ExpectValue)+2, c.queue.Len())
+		}
+	}
+}
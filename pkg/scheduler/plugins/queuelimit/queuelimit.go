@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuelimit
+
+import (
+	"github.com/golang/glog"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+	"volcano.sh/volcano/pkg/apis/helpers"
+)
+
+type queueLimitPlugin struct {
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return queuelimit plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	return &queueLimitPlugin{pluginArguments: arguments}
+}
+
+func (qp *queueLimitPlugin) Name() string {
+	return "queuelimit"
+}
+
+func (qp *queueLimitPlugin) OnSessionOpen(ssn *framework.Session) {
+	counter := newRunningJobCounter(ssn.Jobs)
+
+	jobEnqueueableFn := func(obj interface{}) bool {
+		job := obj.(*api.JobInfo)
+
+		queue, found := ssn.Queues[job.Queue]
+		if !found || queue.Queue == nil {
+			return true
+		}
+
+		limit, found := helpers.GetQueueJobLimit(queue.Queue, vkv1.QueueMaxRunningJobsKey)
+		if !found {
+			return true
+		}
+
+		return counter.enqueueable(job, limit)
+	}
+
+	ssn.AddJobEnqueueableFn(qp.Name(), jobEnqueueableFn)
+}
+
+func (qp *queueLimitPlugin) OnSessionClose(ssn *framework.Session) {}
+
+// runningJobCounter counts the running jobs of queues within a session.
+type runningJobCounter struct {
+	running map[api.QueueID]int32
+	// voted are the jobs voted enqueueable, whose PodGroups may still be left pending by
+	// the enqueue action, e.g. for lack of idle resources or the votes of other plugins.
+	voted map[api.JobID]*api.JobInfo
+}
+
+func newRunningJobCounter(jobs map[api.JobID]*api.JobInfo) *runningJobCounter {
+	return &runningJobCounter{
+		running: countRunningJobs(jobs),
+		voted:   map[api.JobID]*api.JobInfo{},
+	}
+}
+
+// enqueueable returns whether the job is enqueueable under the running limit of its queue.
+func (c *runningJobCounter) enqueueable(job *api.JobInfo, limit int32) bool {
+	c.countEnqueued()
+
+	if c.running[job.Queue] >= limit {
+		glog.V(3).Infof("Queue <%s> has %d running jobs, reached the limit %d; Job <%s/%s> is not enqueueable.",
+			job.Queue, c.running[job.Queue], limit, job.Namespace, job.Name)
+		return false
+	}
+
+	c.voted[job.UID] = job
+	return true
+}
+
+// countEnqueued counts the voted jobs which have been enqueued. The enqueue action updates
+// the phase of PodGroup right after the vote, so the previous votes are settled by now.
+func (c *runningJobCounter) countEnqueued() {
+	for uid, job := range c.voted {
+		if job.PodGroup != nil && job.PodGroup.Status.Phase == kbv1.PodGroupInqueue {
+			c.running[job.Queue]++
+		}
+		delete(c.voted, uid)
+	}
+}
+
+// countRunningJobs returns the number of jobs in each queue whose PodGroup
+// has been enqueued, i.e. is Inqueue, Running or Unknown.
+func countRunningJobs(jobs map[api.JobID]*api.JobInfo) map[api.QueueID]int32 {
+	running := map[api.QueueID]int32{}
+
+	for _, job := range jobs {
+		if job.PodGroup == nil {
+			continue
+		}
+
+		switch job.PodGroup.Status.Phase {
+		case kbv1.PodGroupInqueue, kbv1.PodGroupRunning, kbv1.PodGroupUnknown:
+			running[job.Queue]++
+		}
+	}
+
+	return running
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queuelimit
+
+import (
+	"testing"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+)
+
+func newJob(uid, queue string, phase kbv1.PodGroupPhase) *api.JobInfo {
+	job := api.NewJobInfo(api.JobID(uid))
+	job.Queue = api.QueueID(queue)
+	job.PodGroup = &kbv1.PodGroup{
+		Status: kbv1.PodGroupStatus{
+			Phase: phase,
+		},
+	}
+	return job
+}
+
+func TestCountRunningJobs(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		Jobs        []*api.JobInfo
+		ExpectValue map[api.QueueID]int32
+	}{
+		{
+			Name: "count enqueued jobs",
+			Jobs: []*api.JobInfo{
+				newJob("j1", "q1", kbv1.PodGroupPending),
+				newJob("j2", "q1", kbv1.PodGroupInqueue),
+				newJob("j3", "q1", kbv1.PodGroupRunning),
+				newJob("j4", "q2", kbv1.PodGroupUnknown),
+				newJob("j5", "q2", kbv1.PodGroupPending),
+			},
+			ExpectValue: map[api.QueueID]int32{
+				"q1": 2,
+				"q2": 1,
+			},
+		},
+		{
+			Name: "job without podgroup",
+			Jobs: []*api.JobInfo{
+				api.NewJobInfo("j1"),
+			},
+			ExpectValue: map[api.QueueID]int32{},
+		},
+	}
+
+	for i, testcase := range testCases {
+		jobs := map[api.JobID]*api.JobInfo{}
+		for _, job := range testcase.Jobs {
+			jobs[job.UID] = job
+		}
+
+		running := countRunningJobs(jobs)
+		if len(running) != len(testcase.ExpectValue) {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, running)
+		}
+		for queue, count := range testcase.ExpectValue {
+			if running[queue] != count {
+				t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, running)
+			}
+		}
+	}
+}
+
+func TestRunningJobCounter(t *testing.T) {
+	j1 := newJob("j1", "q1", kbv1.PodGroupPending)
+	j2 := newJob("j2", "q1", kbv1.PodGroupPending)
+	j3 := newJob("j3", "q1", kbv1.PodGroupPending)
+	j4 := newJob("j4", "q1", kbv1.PodGroupPending)
+	counter := newRunningJobCounter(map[api.JobID]*api.JobInfo{
+		"j0": newJob("j0", "q1", kbv1.PodGroupRunning),
+		"j1": j1, "j2": j2, "j3": j3, "j4": j4,
+	})
+
+	// j1 is voted enqueueable but left pending, e.g. for lack of idle resources,
+	// so it is not counted against the limit.
+	if !counter.enqueueable(j1, 2) {
+		t.Errorf("expected j1 enqueueable")
+	}
+	if !counter.enqueueable(j2, 2) {
+		t.Errorf("expected j2 enqueueable")
+	}
+	j2.PodGroup.Status.Phase = kbv1.PodGroupInqueue
+	if counter.enqueueable(j3, 2) {
+		t.Errorf("expected j3 not enqueueable after j2 enqueued")
+	}
+	if counter.enqueueable(j4, 2) {
+		t.Errorf("expected j4 not enqueueable after j2 enqueued")
+	}
+	if counter.running["q1"] != 2 {
+		t.Errorf("expected 2 running jobs in q1, got %d", counter.running["q1"])
+	}
+}
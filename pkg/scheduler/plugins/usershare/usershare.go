@@ -0,0 +1,206 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usershare
+
+import (
+	"github.com/golang/glog"
+
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api/helpers"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/framework"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+const (
+	// GroupKeyArgument is the argument of the annotation or label key used to group jobs;
+	// the user who submitted the job is used by default.
+	GroupKeyArgument = "usershare.groupKey"
+)
+
+type userAttr struct {
+	share     float64
+	allocated *api.Resource
+}
+
+type userSharePlugin struct {
+	totalResource *api.Resource
+
+	// Key is Job ID, jobs of the same user in the same queue share the attribute
+	jobOpts map[api.JobID]*userAttr
+
+	// Key of annotation or label to group jobs
+	groupKey string
+
+	// Arguments given for the plugin
+	pluginArguments framework.Arguments
+}
+
+// New return usershare plugin
+func New(arguments framework.Arguments) framework.Plugin {
+	groupKey := vkv1.JobUserKey
+	if key, found := arguments[GroupKeyArgument]; found && len(key) != 0 {
+		groupKey = key
+	}
+
+	return &userSharePlugin{
+		totalResource:   api.EmptyResource(),
+		jobOpts:         map[api.JobID]*userAttr{},
+		groupKey:        groupKey,
+		pluginArguments: arguments,
+	}
+}
+
+func (up *userSharePlugin) Name() string {
+	return "usershare"
+}
+
+func (up *userSharePlugin) OnSessionOpen(ssn *framework.Session) {
+	// Prepare scheduling data for this session.
+	for _, n := range ssn.Nodes {
+		up.totalResource.Add(n.Allocatable)
+	}
+
+	// Key is queue ID, value is the attributes of users in the queue.
+	queueOpts := map[api.QueueID]map[string]*userAttr{}
+
+	for _, job := range ssn.Jobs {
+		user := getJobGroup(job, up.groupKey)
+
+		if _, found := queueOpts[job.Queue]; !found {
+			queueOpts[job.Queue] = map[string]*userAttr{}
+		}
+		attr, found := queueOpts[job.Queue][user]
+		if !found {
+			attr = &userAttr{
+				allocated: api.EmptyResource(),
+			}
+			queueOpts[job.Queue][user] = attr
+		}
+
+		for status, tasks := range job.TaskStatusIndex {
+			if api.AllocatedStatus(status) {
+				for _, t := range tasks {
+					attr.allocated.Add(t.Resreq)
+				}
+			}
+		}
+
+		up.jobOpts[job.UID] = attr
+	}
+
+	for _, users := range queueOpts {
+		for _, attr := range users {
+			up.updateShare(attr)
+		}
+	}
+
+	jobOrderFn := func(l interface{}, r interface{}) int {
+		lv := l.(*api.JobInfo)
+		rv := r.(*api.JobInfo)
+
+		// Only order jobs between different users in the same queue.
+		if lv.Queue != rv.Queue {
+			return 0
+		}
+
+		lattr, rattr := up.jobOpts[lv.UID], up.jobOpts[rv.UID]
+		if lattr == nil || rattr == nil || lattr == rattr {
+			return 0
+		}
+
+		glog.V(4).Infof("UserShare JobOrderFn: <%v/%v> user share: %v, <%v/%v> user share: %v",
+			lv.Namespace, lv.Name, lattr.share, rv.Namespace, rv.Name, rattr.share)
+
+		if lattr.share == rattr.share {
+			return 0
+		}
+
+		if lattr.share < rattr.share {
+			return -1
+		}
+
+		return 1
+	}
+
+	ssn.AddJobOrderFn(up.Name(), jobOrderFn)
+
+	// Register event handlers.
+	ssn.AddEventHandler(&framework.EventHandler{
+		AllocateFunc: func(event *framework.Event) {
+			attr, found := up.jobOpts[event.Task.Job]
+			if !found {
+				return
+			}
+			attr.allocated.Add(event.Task.Resreq)
+
+			up.updateShare(attr)
+
+			glog.V(4).Infof("UserShare AllocateFunc: task <%v/%v>, resreq <%v>, share <%v>",
+				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
+		},
+		DeallocateFunc: func(event *framework.Event) {
+			attr, found := up.jobOpts[event.Task.Job]
+			if !found {
+				return
+			}
+			attr.allocated.Sub(event.Task.Resreq)
+
+			up.updateShare(attr)
+
+			glog.V(4).Infof("UserShare EvictFunc: task <%v/%v>, resreq <%v>, share <%v>",
+				event.Task.Namespace, event.Task.Name, event.Task.Resreq, attr.share)
+		},
+	})
+}
+
+func (up *userSharePlugin) updateShare(attr *userAttr) {
+	attr.share = calculateShare(attr.allocated, up.totalResource)
+}
+
+func (up *userSharePlugin) OnSessionClose(ssn *framework.Session) {
+	// Clean schedule data.
+	up.totalResource = api.EmptyResource()
+	up.jobOpts = map[api.JobID]*userAttr{}
+}
+
+// calculateShare returns the dominant share of allocated resources.
+func calculateShare(allocated, totalResource *api.Resource) float64 {
+	res := float64(0)
+	for _, rn := range totalResource.ResourceNames() {
+		share := helpers.Share(allocated.Get(rn), totalResource.Get(rn))
+		if share > res {
+			res = share
+		}
+	}
+
+	return res
+}
+
+// getJobGroup returns the group of job by the annotation or label of its PodGroup, which are
+// copied from the Job by the job controller; jobs without the key are grouped together.
+func getJobGroup(job *api.JobInfo, key string) string {
+	if job.PodGroup == nil {
+		return ""
+	}
+
+	if group, found := job.PodGroup.Annotations[key]; found {
+		return group
+	}
+
+	return job.PodGroup.Labels[key]
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Volcano Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usershare
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kbv1 "github.com/kubernetes-sigs/kube-batch/pkg/apis/scheduling/v1alpha1"
+	"github.com/kubernetes-sigs/kube-batch/pkg/scheduler/api"
+
+	vkv1 "volcano.sh/volcano/pkg/apis/batch/v1alpha1"
+)
+
+func TestGetJobGroup(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		PodGroup    *kbv1.PodGroup
+		Key         string
+		ExpectValue string
+	}{
+		{
+			Name: "group by user annotation",
+			PodGroup: &kbv1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{vkv1.JobUserKey: "alice"},
+				},
+			},
+			Key:         vkv1.JobUserKey,
+			ExpectValue: "alice",
+		},
+		{
+			Name: "group by label",
+			PodGroup: &kbv1.PodGroup{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{vkv1.JobUserKey: "alice"},
+					Labels:      map[string]string{"team": "research"},
+				},
+			},
+			Key:         "team",
+			ExpectValue: "research",
+		},
+		{
+			Name:        "job without podgroup",
+			PodGroup:    nil,
+			Key:         vkv1.JobUserKey,
+			ExpectValue: "",
+		},
+	}
+
+	for i, testcase := range testCases {
+		job := api.NewJobInfo("job")
+		job.PodGroup = testcase.PodGroup
+
+		if group := getJobGroup(job, testcase.Key); group != testcase.ExpectValue {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, group)
+		}
+	}
+}
+
+func TestCalculateShare(t *testing.T) {
+	total := &api.Resource{MilliCPU: 4000, Memory: 4096}
+
+	testCases := []struct {
+		Name        string
+		Allocated   *api.Resource
+		ExpectValue float64
+	}{
+		{
+			Name:        "nothing allocated",
+			Allocated:   api.EmptyResource(),
+			ExpectValue: 0,
+		},
+		{
+			Name:        "cpu is dominant",
+			Allocated:   &api.Resource{MilliCPU: 2000, Memory: 1024},
+			ExpectValue: 0.5,
+		},
+		{
+			Name:        "memory is dominant",
+			Allocated:   &api.Resource{MilliCPU: 1000, Memory: 3072},
+			ExpectValue: 0.75,
+		},
+	}
+
+	for i, testcase := range testCases {
+		if share := calculateShare(testcase.Allocated, total); share != testcase.ExpectValue {
+			t.Errorf("case %d (%s): expected: %v, got %v ", i, testcase.Name, testcase.ExpectValue, share)
+		}
+	}
+}